@@ -11,8 +11,10 @@ require (
 	github.com/ralvarezdev/go-reflect v0.3.1
 	github.com/ralvarezdev/go-strings v0.2.3
 	github.com/ralvarezdev/go-validator v0.7.5
+	github.com/redis/go-redis/v9 v9.16.0
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20251111163417-95abcf5c77ba
 	google.golang.org/grpc v1.76.0
+	google.golang.org/protobuf v1.36.10
 )
 
 require (
@@ -20,8 +22,6 @@ require (
 	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
 	github.com/golang-jwt/jwt/v5 v5.3.0 // indirect
 	github.com/ralvarezdev/go-databases v0.9.0 // indirect
-	github.com/redis/go-redis/v9 v9.16.0 // indirect
 	golang.org/x/crypto v0.45.0 // indirect
 	golang.org/x/sys v0.38.0 // indirect
-	google.golang.org/protobuf v1.36.10 // indirect
 )
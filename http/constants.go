@@ -2,6 +2,7 @@ package http
 
 import (
 	"net/http"
+	"time"
 )
 
 const (
@@ -10,6 +11,49 @@ const (
 
 	// Authorization is the header key for the Authorization header
 	Authorization = "Authorization"
+
+	// Accept is the header key for the Accept header
+	Accept = "Accept"
+
+	// Vary is the header key for the Vary header
+	Vary = "Vary"
+
+	// Link is the header key for the Link header
+	Link = "Link"
+
+	// Deprecation is the header key for the Deprecation header
+	Deprecation = "Deprecation"
+
+	// Sunset is the header key for the Sunset header
+	Sunset = "Sunset"
+
+	// ContentType is the header key for the Content-Type header
+	ContentType = "Content-Type"
+
+	// ETag is the header key for the ETag header
+	ETag = "ETag"
+
+	// LastModified is the header key for the Last-Modified header
+	LastModified = "Last-Modified"
+
+	// IfMatch is the header key for the If-Match header
+	IfMatch = "If-Match"
+
+	// IfNoneMatch is the header key for the If-None-Match header
+	IfNoneMatch = "If-None-Match"
+
+	// IfModifiedSince is the header key for the If-Modified-Since header
+	IfModifiedSince = "If-Modified-Since"
+
+	// IfUnmodifiedSince is the header key for the If-Unmodified-Since header
+	IfUnmodifiedSince = "If-Unmodified-Since"
+
+	// DefaultVersionHeader is the default header key for the API version, used by the header versioning strategy
+	DefaultVersionHeader = "X-API-Version"
+
+	// DefaultShutdownTimeout is the default time the server waits for the in-flight requests when the module tree is
+	// served with Serve
+	DefaultShutdownTimeout = 30 * time.Second
 )
 
 var (
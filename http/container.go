@@ -0,0 +1,139 @@
+package http
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+type (
+	// Container is the typed dependencies container shared by a module tree, its values are keyed by their type,
+	// usually an interface
+	Container struct {
+		values map[reflect.Type]any
+		mutex  sync.RWMutex
+	}
+)
+
+// NewContainer creates a new dependencies container
+//
+// Returns:
+//
+//   - *Container: The container
+func NewContainer() *Container {
+	return &Container{
+		values: make(map[reflect.Type]any),
+	}
+}
+
+// Dependency returns the key of a dependency type, used to declare the dependencies a module provides or consumes
+//
+// Returns:
+//
+//   - reflect.Type: The dependency key
+func Dependency[T any]() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+// Has checks if a dependency is provided
+//
+// Parameters:
+//
+//   - key: The dependency key
+//
+// Returns:
+//
+//   - bool: True if the dependency is provided, false otherwise
+func (c *Container) Has(key reflect.Type) bool {
+	if c == nil {
+		return false
+	}
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	_, ok := c.values[key]
+	return ok
+}
+
+// Provide provides a dependency to the container, keyed by its type parameter. The type parameter can't be inferred
+// from the value, so it must be explicit, e.g. Provide[UsersService](c, service), since a value keyed by its concrete
+// type wouldn't be resolved by the consumers of its interface
+//
+// Parameters:
+//
+//   - c: The container
+//   - value: The dependency value, it must be assignable to T
+//
+// Returns:
+//
+//   - error: The error if any
+func Provide[T any](c *Container, value any) error {
+	if c == nil {
+		return ErrNilContainer
+	}
+
+	// Check the value type, a nil value provides the zero value of T
+	key := Dependency[T]()
+	typedValue, ok := value.(T)
+	if !ok && value != nil {
+		return fmt.Errorf(ErrDependencyTypeMismatch, key, value)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.values[key]; ok {
+		return fmt.Errorf(ErrDependencyAlreadyProvided, key)
+	}
+	c.values[key] = typedValue
+	return nil
+}
+
+// Resolve resolves a dependency from the container, keyed by its type parameter
+//
+// Parameters:
+//
+//   - c: The container
+//
+// Returns:
+//
+//   - T: The dependency value
+//   - error: The error if any
+func Resolve[T any](c *Container) (T, error) {
+	var zero T
+	if c == nil {
+		return zero, ErrNilContainer
+	}
+
+	key := Dependency[T]()
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	value, ok := c.values[key]
+	if !ok {
+		return zero, fmt.Errorf(ErrDependencyNotFound, key)
+	}
+
+	// The nil interface values are stored as nil
+	typedValue, _ := value.(T)
+	return typedValue, nil
+}
+
+// MustResolve resolves a dependency from the container, panicking if it's not provided. It's intended for the
+// module functions, whose dependencies were already checked by Create
+//
+// Parameters:
+//
+//   - c: The container
+//
+// Returns:
+//
+//   - T: The dependency value
+func MustResolve[T any](c *Container) T {
+	value, err := Resolve[T](c)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
@@ -14,4 +14,19 @@ const (
 
 	// CtxWildcardsKey is the context key for the wildcard
 	CtxWildcardsKey ContextKey = "wildcards"
+
+	// CtxPrincipalKey is the context key for the authenticated principal
+	CtxPrincipalKey ContextKey = "principal"
+
+	// CtxScopesKey is the context key for the authenticated principal scopes
+	CtxScopesKey ContextKey = "scopes"
+
+	// CtxAPIVersionKey is the context key for the resolved API version
+	CtxAPIVersionKey ContextKey = "api_version"
+
+	// CtxRouteKey is the context key for the matched route
+	CtxRouteKey ContextKey = "route"
+
+	// CtxJSONDecoderOptionsKey is the context key for the JSON decoder options of the route
+	CtxJSONDecoderOptionsKey ContextKey = "json_decoder_options"
 )
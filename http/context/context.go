@@ -138,3 +138,129 @@ func GetCtxQueryParameters(r *http.Request) map[string][]string {
 func GetQueryParameters(r *http.Request) map[string][]string {
 	return GetCtxQueryParameters(r)
 }
+
+// SetCtxPrincipal sets the authenticated principal in the context
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//   - principal: The principal to set in the context
+//
+// Returns:
+//
+//   - *http.Request: The HTTP request with the principal set in the context
+func SetCtxPrincipal(r *http.Request, principal string) *http.Request {
+	ctx := context.WithValue(r.Context(), CtxPrincipalKey, principal)
+	return r.WithContext(ctx)
+}
+
+// SetPrincipal wraps SetCtxPrincipal
+func SetPrincipal(r *http.Request, principal string) *http.Request {
+	return SetCtxPrincipal(r, principal)
+}
+
+// GetCtxPrincipal tries to get the authenticated principal from the context
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//
+// Returns:
+//
+//   - string: The principal from the context, or an empty string if not found
+func GetCtxPrincipal(r *http.Request) string {
+	principal, ok := r.Context().Value(CtxPrincipalKey).(string)
+	if !ok {
+		return ""
+	}
+	return principal
+}
+
+// GetPrincipal wraps GetCtxPrincipal
+func GetPrincipal(r *http.Request) string {
+	return GetCtxPrincipal(r)
+}
+
+// SetCtxScopes sets the authenticated principal scopes in the context
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//   - scopes: The scopes to set in the context
+//
+// Returns:
+//
+//   - *http.Request: The HTTP request with the scopes set in the context
+func SetCtxScopes(r *http.Request, scopes []string) *http.Request {
+	ctx := context.WithValue(r.Context(), CtxScopesKey, scopes)
+	return r.WithContext(ctx)
+}
+
+// SetScopes wraps SetCtxScopes
+func SetScopes(r *http.Request, scopes []string) *http.Request {
+	return SetCtxScopes(r, scopes)
+}
+
+// GetCtxScopes tries to get the authenticated principal scopes from the context
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//
+// Returns:
+//
+//   - []string: The scopes from the context, or nil if not found
+func GetCtxScopes(r *http.Request) []string {
+	scopes, ok := r.Context().Value(CtxScopesKey).([]string)
+	if !ok {
+		return nil
+	}
+	return scopes
+}
+
+// GetScopes wraps GetCtxScopes
+func GetScopes(r *http.Request) []string {
+	return GetCtxScopes(r)
+}
+
+// SetCtxAPIVersion sets the resolved API version in the context
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//   - version: The API version to set in the context
+//
+// Returns:
+//
+//   - *http.Request: The HTTP request with the API version set in the context
+func SetCtxAPIVersion(r *http.Request, version string) *http.Request {
+	ctx := context.WithValue(r.Context(), CtxAPIVersionKey, version)
+	return r.WithContext(ctx)
+}
+
+// SetAPIVersion wraps SetCtxAPIVersion
+func SetAPIVersion(r *http.Request, version string) *http.Request {
+	return SetCtxAPIVersion(r, version)
+}
+
+// GetCtxAPIVersion tries to get the resolved API version from the context
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//
+// Returns:
+//
+//   - string: The API version from the context, or an empty string if not found
+func GetCtxAPIVersion(r *http.Request) string {
+	version, ok := r.Context().Value(CtxAPIVersionKey).(string)
+	if !ok {
+		return ""
+	}
+	return version
+}
+
+// GetAPIVersion wraps GetCtxAPIVersion
+func GetAPIVersion(r *http.Request) string {
+	return GetCtxAPIVersion(r)
+}
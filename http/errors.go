@@ -5,19 +5,55 @@ import (
 )
 
 var (
-	ErrCodeCookieNotFound string
+	ErrCodeCookieNotFound     string
+	ErrCodeUnsupportedVersion string
 )
 
 const (
-	ErrInvalidRequestBody = "invalid request body type, expected: %v"
-	ErrNilSubmodule       = "%s: submodule at index %d is nil"
+	ErrInvalidRequestBody        = "invalid request body type, expected: %v"
+	ErrNilSubmodule              = "%s: submodule at index %d is nil"
+	ErrNilVersion                = "%s: version at index %d is nil"
+	ErrEmptyVersionName          = "%s: version at index %d has an empty name"
+	ErrDuplicateVersion          = "%s: version %s is declared more than once"
+	ErrUnknownInheritedVersion   = "%s: version %s inherits the undeclared version %s, it must be declared before"
+	ErrUnknownDefaultVersion     = "%s: default version %s is not declared"
+	ErrNilDependency             = "%s: dependency at index %d is nil"
+	ErrDependencyAlreadyProvided = "dependency %v is already provided"
+	ErrDependencyNotFound        = "dependency %v is not provided"
+	ErrDependencyTypeMismatch    = "dependency %v cannot be provided with a value of type %T"
+	ErrDuplicateProvider         = "%s: dependency %v is already provided by the module %s"
+	ErrMissingDependency         = "%s: consumes the dependency %v, but no module provides it"
+	ErrCyclicDependency          = "%s: cyclic dependency between the modules: %s"
+	ErrUnprovidedDependency      = "%s: declares the dependency %v, but its provide function didn't provide it"
+	ErrProvideFailed             = "%s: failed to provide the dependencies: %w"
+	ErrStartFailed               = "%s: failed to start: %w"
+	ErrStopFailed                = "%s: failed to stop: %w"
+	ErrNilRegisteredMiddleware   = "middleware %s cannot be nil"
+	ErrDuplicateMiddleware       = "middleware %s is already registered"
+	ErrUnknownMiddleware         = "module %s: unknown middleware %s"
+	ErrUnknownModules            = "unknown modules on the configuration: %s"
+	ErrDuplicateModuleName       = "module name %s is used more than once"
+	ErrInvalidModulesConfig      = "invalid modules configuration: %w"
+	ErrInvalidModuleEnv          = "invalid modules configuration environment variable %s: %w"
 )
 
 var (
-	ErrCookieNotFound = errors.New("cookie not found")
-	ErrNilRequestBody = errors.New("request body cannot be nil")
-	ErrInDevelopment  = errors.New("in development")
-	ErrNilModule      = errors.New("module cannot be nil")
+	ErrCookieNotFound        = errors.New("cookie not found")
+	ErrNilRequestBody        = errors.New("request body cannot be nil")
+	ErrInDevelopment         = errors.New("in development")
+	ErrNilModule             = errors.New("module cannot be nil")
+	ErrNilVersionedModule    = errors.New("versioned module cannot be nil")
+	ErrNoVersions            = errors.New("versioned module must declare at least one version")
+	ErrUnsupportedVersion    = errors.New("unsupported API version")
+	ErrNilVendor             = errors.New("vendor cannot be empty for the media type versioning strategy")
+	ErrNilContainer          = errors.New("container cannot be nil")
+	ErrModuleNotCreated      = errors.New("module must be created before starting or stopping it")
+	ErrNilServer             = errors.New("server cannot be nil")
+	ErrNilServerHandler      = errors.New("server handler cannot be nil")
+	ErrNilModulesConfig      = errors.New("modules configuration cannot be nil")
+	ErrNilMiddlewareRegistry = errors.New("middleware registry cannot be nil")
+	ErrEmptyMiddlewareName   = errors.New("middleware name cannot be empty")
+	ErrNilNamedMiddleware    = errors.New("named middleware cannot be nil")
 )
 
 var (
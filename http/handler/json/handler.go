@@ -34,6 +34,26 @@ type (
 func NewHandler(
 	mode *goflagsmode.Flag,
 	rawErrorHandler gonethttphandler.RawErrorHandler,
+) (*Handler, error) {
+	return NewHandlerWithOptions(mode, rawErrorHandler, nil)
+}
+
+// NewHandlerWithOptions creates a new JSON handler that decodes the request bodies following the options
+//
+// Parameters:
+//
+//   - mode: the flag mode
+//   - rawErrorHandler: the raw error handler
+//   - options: the JSON decoder options, can be overridden per route with gonethttprequestjson.OptionsMiddleware
+//
+// Returns:
+//
+//   - *Handler: the created JSON handler
+//   - error: the error if any
+func NewHandlerWithOptions(
+	mode *goflagsmode.Flag,
+	rawErrorHandler gonethttphandler.RawErrorHandler,
+	options *gonethttprequestjson.Options,
 ) (*Handler, error) {
 	// Create the JSON encoder
 	encoder := gonethttpresponsejson.NewEncoder(mode)
@@ -49,9 +69,10 @@ func NewHandler(
 	}
 
 	// Create the JSON decoder
-	decoder, err := gonethttprequestjson.NewDecoder(
+	decoder, err := gonethttprequestjson.NewDecoderWithOptions(
 		mode,
 		gojsondecoderjson.NewDecoder(),
+		options,
 	)
 	if err != nil {
 		return nil, err
@@ -41,6 +41,31 @@ func NewStreamHandler(
 ) (
 	*StreamHandler,
 	error,
+) {
+	return NewStreamHandlerWithOptions(mode, rawErrorhandler, logger, nil)
+}
+
+// NewStreamHandlerWithOptions creates a new JSON handler that decodes the request bodies following the options
+//
+// Parameters:
+//
+//   - mode: the flag mode
+//   - rawErrorhandler: the raw error handler
+//   - logger: the logger
+//   - options: the JSON decoder options, can be overridden per route with gonethttprequestjson.OptionsMiddleware
+//
+// Returns:
+//
+//   - *StreamHandler: the created JSON handler
+//   - error: the error if any
+func NewStreamHandlerWithOptions(
+	mode *goflagsmode.Flag,
+	rawErrorhandler gonethttphandler.RawErrorHandler,
+	logger *slog.Logger,
+	options *gonethttprequestjson.Options,
+) (
+	*StreamHandler,
+	error,
 ) {
 	// Create the JSON stream encoder
 	streamEncoder := gonethttpresponsejson.NewStreamEncoder(mode, logger)
@@ -56,9 +81,10 @@ func NewStreamHandler(
 	}
 
 	// Create the JSON stream decoder
-	streamDecoder, err := gonethttprequestjson.NewDecoder(
+	streamDecoder, err := gonethttprequestjson.NewDecoderWithOptions(
 		mode,
 		gojsondecoderjson.NewStreamDecoder(),
+		options,
 	)
 	if err != nil {
 		return nil, err
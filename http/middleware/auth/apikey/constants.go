@@ -0,0 +1,9 @@
+package apikey
+
+const (
+	// DefaultHeaderName is the default header name to read the API key from
+	DefaultHeaderName = "X-API-Key"
+
+	// DefaultPrefixSeparator is the default separator between the API key prefix and its secret
+	DefaultPrefixSeparator = "."
+)
@@ -0,0 +1,30 @@
+package apikey
+
+import (
+	"errors"
+)
+
+var (
+	ErrCodeMissingAPIKey      string
+	ErrCodeInvalidAPIKey      string
+	ErrCodeExpiredAPIKey      string
+	ErrCodeInsufficientScopes string
+	ErrCodeAPIKeyLookupFailed string
+)
+
+const (
+	ErrMissingScope = "api key is missing the required scope: %s"
+)
+
+var (
+	ErrNilStore            = errors.New("api key store cannot be nil")
+	ErrNilKey              = errors.New("api key cannot be nil")
+	ErrEmptyKeyPrefix      = errors.New("api key prefix cannot be empty")
+	ErrEmptyKeySource      = errors.New("api key header name and query parameter name cannot be both empty")
+	ErrMissingAPIKey       = errors.New("missing api key")
+	ErrInvalidAPIKey       = errors.New("invalid api key")
+	ErrExpiredAPIKey       = errors.New("api key has expired")
+	ErrKeyNotFound         = errors.New("api key not found")
+	ErrAPIKeyLookupFailed  = errors.New("failed to look up api key")
+	ErrKeyPrefixDuplicated = errors.New("api key prefix already registered")
+)
@@ -0,0 +1,25 @@
+package apikey
+
+import (
+	"context"
+	"net/http"
+)
+
+type (
+	// Store is the interface for the hashed API keys storage
+	Store interface {
+		GetKey(ctx context.Context, prefix string) (*Key, error)
+	}
+
+	// Authenticator interface
+	Authenticator interface {
+		Authenticate() func(next http.Handler) http.Handler
+		AuthenticateWithScopes(scopes ...string) func(next http.Handler) http.Handler
+	}
+
+	// MetadataAuthenticator is the optional interface of the authenticators that get the required scopes from the
+	// route metadata
+	MetadataAuthenticator interface {
+		AuthenticateFromMetadata() func(next http.Handler) http.Handler
+	}
+)
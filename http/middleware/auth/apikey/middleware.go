@@ -0,0 +1,333 @@
+package apikey
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	gonethttp "github.com/ralvarezdev/go-net/http"
+	gonethttpctx "github.com/ralvarezdev/go-net/http/context"
+	gonethttphandler "github.com/ralvarezdev/go-net/http/handler"
+	gonethttproute "github.com/ralvarezdev/go-net/http/route"
+)
+
+type (
+	// Middleware struct is the API key authentication middleware
+	Middleware struct {
+		store            Store
+		responsesHandler gonethttphandler.ResponsesHandler
+		options          *Options
+		logger           *slog.Logger
+	}
+
+	// Options is the options for the API key authentication middleware
+	Options struct {
+		HeaderName         string
+		QueryParameterName string
+		PrefixSeparator    string
+	}
+)
+
+// NewOptions creates a new Options struct
+//
+// Parameters:
+//
+//   - headerName: The name of the header that contains the API key (can be empty if a query parameter is set)
+//   - queryParameterName: The name of the query parameter that contains the API key (can be empty)
+//   - prefixSeparator: The separator between the API key prefix and its secret
+//
+// Returns:
+//
+//   - *Options: The options for the API key authentication middleware
+func NewOptions(
+	headerName,
+	queryParameterName,
+	prefixSeparator string,
+) *Options {
+	return &Options{
+		headerName,
+		queryParameterName,
+		prefixSeparator,
+	}
+}
+
+// NewMiddleware creates a new API key authentication middleware
+//
+// Parameters:
+//
+//   - responsesHandler: The HTTP handler to handle errors
+//   - store: The hashed API keys store
+//   - options: The options for the API key authentication middleware (can be nil)
+//   - logger: The logger (can be nil)
+//
+// Returns:
+//
+//   - *Middleware: The API key authentication middleware
+//   - error: The error if any
+func NewMiddleware(
+	responsesHandler gonethttphandler.ResponsesHandler,
+	store Store,
+	options *Options,
+	logger *slog.Logger,
+) (*Middleware, error) {
+	// Check if either the responses handler or the store is nil
+	if responsesHandler == nil {
+		return nil, gonethttphandler.ErrNilHandler
+	}
+	if store == nil {
+		return nil, ErrNilStore
+	}
+
+	// Set the default options
+	if options == nil {
+		options = NewOptions(DefaultHeaderName, "", DefaultPrefixSeparator)
+	}
+	if options.HeaderName == "" && options.QueryParameterName == "" {
+		return nil, ErrEmptyKeySource
+	}
+	if options.PrefixSeparator == "" {
+		options.PrefixSeparator = DefaultPrefixSeparator
+	}
+
+	if logger != nil {
+		logger = logger.With(
+			slog.String("component", "http_middleware_auth_apikey"),
+		)
+	}
+
+	return &Middleware{
+		store,
+		responsesHandler,
+		options,
+		logger,
+	}, nil
+}
+
+// getRawKey gets the raw API key from the request
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//
+// Returns:
+//
+//   - string: The raw API key
+//   - string: The name of the header or query parameter the raw API key was read from
+func (m Middleware) getRawKey(r *http.Request) (rawKey, field string) {
+	// Check the header first
+	if m.options.HeaderName != "" {
+		rawKey = strings.TrimSpace(r.Header.Get(m.options.HeaderName))
+		if rawKey != "" {
+			return rawKey, m.options.HeaderName
+		}
+	}
+
+	// Fall back to the query parameter
+	if m.options.QueryParameterName != "" {
+		rawKey = strings.TrimSpace(r.URL.Query().Get(m.options.QueryParameterName))
+		if rawKey != "" {
+			return rawKey, m.options.QueryParameterName
+		}
+	}
+
+	// Report the missing key on the preferred source
+	if m.options.HeaderName != "" {
+		return "", m.options.HeaderName
+	}
+	return "", m.options.QueryParameterName
+}
+
+// failHandler is the default fail handler for the API key authentication
+//
+// Parameters:
+//
+//   - w: The HTTP response writer
+//   - r: The HTTP request
+//   - field: The name of the header or query parameter that contains the API key
+//   - err: The error that occurred
+//   - errorCode: The error code to return
+//   - httpStatus: The HTTP status code to return
+func (m Middleware) failHandler(
+	w http.ResponseWriter,
+	r *http.Request,
+	field string,
+	err error,
+	errorCode string,
+	httpStatus int,
+) {
+	m.responsesHandler.HandleFailFieldErrorWithCode(
+		w,
+		r,
+		field,
+		err,
+		errorCode,
+		httpStatus,
+	)
+}
+
+// authenticate authenticates the request with an API key, checking that the key has all the given scopes, and calls
+// the next handler
+//
+// Parameters:
+//
+//   - w: The HTTP response writer
+//   - r: The HTTP request
+//   - next: The next handler
+//   - scopes: The scopes required by the route
+func (m Middleware) authenticate(
+	w http.ResponseWriter,
+	r *http.Request,
+	next http.Handler,
+	scopes []string,
+) {
+	// Get the raw API key from the request
+	rawKey, field := m.getRawKey(r)
+	if rawKey == "" {
+		m.failHandler(
+			w,
+			r,
+			field,
+			ErrMissingAPIKey,
+			ErrCodeMissingAPIKey,
+			http.StatusUnauthorized,
+		)
+		return
+	}
+
+	// Get the prefix to identify the key
+	prefix, _, ok := SplitKey(rawKey, m.options.PrefixSeparator)
+	if !ok {
+		m.failHandler(
+			w,
+			r,
+			field,
+			ErrInvalidAPIKey,
+			ErrCodeInvalidAPIKey,
+			http.StatusUnauthorized,
+		)
+		return
+	}
+
+	// Look up the key
+	key, err := m.store.GetKey(r.Context(), prefix)
+	if err != nil && !errors.Is(err, ErrKeyNotFound) {
+		if m.logger != nil {
+			m.logger.Error(
+				"Failed to look up api key",
+				slog.String("prefix", prefix),
+				slog.Any("error", err),
+			)
+		}
+		m.responsesHandler.HandleDebugErrorWithCode(
+			w,
+			r,
+			err,
+			gonethttp.ErrInternalServerError,
+			ErrCodeAPIKeyLookupFailed,
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	// Check if the key exists, is not revoked and matches the stored hash
+	if key == nil || key.Revoked || !key.Matches(rawKey) {
+		m.failHandler(
+			w,
+			r,
+			field,
+			ErrInvalidAPIKey,
+			ErrCodeInvalidAPIKey,
+			http.StatusUnauthorized,
+		)
+		return
+	}
+
+	// Check if the key has expired
+	if key.IsExpired() {
+		m.failHandler(
+			w,
+			r,
+			field,
+			ErrExpiredAPIKey,
+			ErrCodeExpiredAPIKey,
+			http.StatusUnauthorized,
+		)
+		return
+	}
+
+	// Check the required scopes
+	if missingScope, hasScopes := key.HasScopes(scopes...); !hasScopes {
+		m.failHandler(
+			w,
+			r,
+			field,
+			fmt.Errorf(ErrMissingScope, missingScope),
+			ErrCodeInsufficientScopes,
+			http.StatusForbidden,
+		)
+		return
+	}
+
+	// Set the principal and the scopes to the context
+	r = gonethttpctx.SetCtxPrincipal(r, key.Principal)
+	r = gonethttpctx.SetCtxScopes(r, key.Scopes)
+
+	// Call the next handler
+	next.ServeHTTP(w, r)
+}
+
+// AuthenticateWithScopes return the middleware function that authenticates the request with an API key, and checks
+// that the key has all the given scopes
+//
+// Parameters:
+//
+//   - scopes: The scopes required by the route
+//
+// Returns:
+//
+//   - func(next http.Handler) http.Handler: The middleware function
+func (m Middleware) AuthenticateWithScopes(
+	scopes ...string,
+) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				m.authenticate(w, r, next, scopes)
+			},
+		)
+	}
+}
+
+// Authenticate return the middleware function that authenticates the request with an API key
+//
+// Returns:
+//
+//   - func(next http.Handler) http.Handler: The middleware function
+func (m Middleware) Authenticate() func(next http.Handler) http.Handler {
+	return m.AuthenticateWithScopes()
+}
+
+// AuthenticateFromMetadata authenticates the requests requiring the scopes of the matched route metadata, so it can
+// be applied once to a whole module
+//
+// Returns:
+//
+//   - func(next http.Handler) http.Handler: The middleware function
+func (m Middleware) AuthenticateFromMetadata() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				// Get the required scopes from the route metadata
+				var scopes []string
+				if metadata := gonethttproute.GetCtxRouteMetadata(r); metadata != nil {
+					scopes = metadata.Scopes
+				}
+
+				// Authenticate the request with the required scopes
+				m.authenticate(w, r, next, scopes)
+			},
+		)
+	}
+}
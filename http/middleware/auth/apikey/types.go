@@ -0,0 +1,205 @@
+package apikey
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// Key is the stored representation of an API key
+	Key struct {
+		Prefix    string
+		Hash      []byte
+		Principal string
+		Scopes    []string
+		ExpiresAt time.Time
+		Revoked   bool
+	}
+
+	// MemoryStore is an in-memory implementation of the Store interface
+	MemoryStore struct {
+		keys  map[string]*Key
+		mutex sync.RWMutex
+	}
+)
+
+// HashKey hashes a raw API key
+//
+// Parameters:
+//
+//   - rawKey: The raw API key
+//
+// Returns:
+//
+//   - []byte: The SHA-256 hash of the raw API key
+func HashKey(rawKey string) []byte {
+	hash := sha256.Sum256([]byte(rawKey))
+	return hash[:]
+}
+
+// SplitKey splits a raw API key into its prefix and its secret
+//
+// Parameters:
+//
+//   - rawKey: The raw API key
+//   - separator: The separator between the prefix and the secret
+//
+// Returns:
+//
+//   - string: The prefix
+//   - string: The secret
+//   - bool: True if the raw API key contains both a prefix and a secret
+func SplitKey(rawKey, separator string) (prefix, secret string, ok bool) {
+	prefix, secret, ok = strings.Cut(rawKey, separator)
+	if !ok || prefix == "" || secret == "" {
+		return "", "", false
+	}
+	return prefix, secret, true
+}
+
+// Matches checks if the raw API key matches the stored hash in constant time
+//
+// Parameters:
+//
+//   - rawKey: The raw API key
+//
+// Returns:
+//
+//   - bool: True if the raw API key matches the stored hash
+func (k *Key) Matches(rawKey string) bool {
+	if k == nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(HashKey(rawKey), k.Hash) == 1
+}
+
+// IsExpired checks if the API key has expired
+//
+// Returns:
+//
+//   - bool: True if the API key has an expiration time, and it has passed
+func (k *Key) IsExpired() bool {
+	if k == nil {
+		return true
+	}
+	return !k.ExpiresAt.IsZero() && time.Now().After(k.ExpiresAt)
+}
+
+// HasScopes checks if the API key has all the given scopes
+//
+// Parameters:
+//
+//   - scopes: The scopes to check
+//
+// Returns:
+//
+//   - string: The first missing scope, if any
+//   - bool: True if the API key has all the given scopes
+func (k *Key) HasScopes(scopes ...string) (string, bool) {
+	if k == nil {
+		return "", false
+	}
+
+	// Create a set of the key scopes
+	keyScopes := make(map[string]struct{}, len(k.Scopes))
+	for _, scope := range k.Scopes {
+		keyScopes[scope] = struct{}{}
+	}
+
+	// Check every required scope
+	for _, scope := range scopes {
+		if _, ok := keyScopes[scope]; !ok {
+			return scope, false
+		}
+	}
+	return "", true
+}
+
+// NewMemoryStore creates a new in-memory API keys store
+//
+// Returns:
+//
+//   - *MemoryStore: The in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		keys: make(map[string]*Key),
+	}
+}
+
+// AddKey adds a hashed API key to the store
+//
+// Parameters:
+//
+//   - key: The API key to add
+//
+// Returns:
+//
+//   - error: The error if any
+func (m *MemoryStore) AddKey(key *Key) error {
+	if m == nil {
+		return ErrNilStore
+	}
+
+	// Check the key
+	if key == nil {
+		return ErrNilKey
+	}
+	if key.Prefix == "" {
+		return ErrEmptyKeyPrefix
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Check if the prefix is already registered
+	if _, ok := m.keys[key.Prefix]; ok {
+		return ErrKeyPrefixDuplicated
+	}
+	m.keys[key.Prefix] = key
+	return nil
+}
+
+// RemoveKey removes an API key from the store
+//
+// Parameters:
+//
+//   - prefix: The prefix of the API key to remove
+func (m *MemoryStore) RemoveKey(prefix string) {
+	if m == nil {
+		return
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.keys, prefix)
+}
+
+// GetKey gets an API key from the store by its prefix
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - prefix: The prefix of the API key
+//
+// Returns:
+//
+//   - *Key: The API key
+//   - error: The error if any
+func (m *MemoryStore) GetKey(_ context.Context, prefix string) (*Key, error) {
+	if m == nil {
+		return nil, ErrNilStore
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	key, ok := m.keys[prefix]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return key, nil
+}
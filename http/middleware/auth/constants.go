@@ -0,0 +1,21 @@
+package auth
+
+import (
+	"time"
+)
+
+const (
+	// SecWebSocketProtocol is the header key for the Sec-WebSocket-Protocol header
+	SecWebSocketProtocol = "Sec-WebSocket-Protocol"
+
+	// DefaultWebSocketProtocolPrefix is the default prefix of the WebSocket subprotocol that carries the token
+	DefaultWebSocketProtocolPrefix = "bearer."
+
+	// DefaultRefreshResultTTL is the default time a token refresh result is shared with the requests that carry the
+	// same refresh token
+	DefaultRefreshResultTTL = 10 * time.Second
+
+	// DefaultRotatedTokenTTL is the default time the replay of a rotated refresh token is detected, used when the
+	// refresh token has no expiration claim
+	DefaultRotatedTokenTTL = 7 * 24 * time.Hour
+)
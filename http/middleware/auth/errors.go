@@ -8,6 +8,12 @@ var (
 	ErrCodeInvalidAuthorizationHeader string
 	ErrCodeInvalidTokenClaims         string
 	ErrCodeFailedToRefreshToken       string
+	ErrCodeMissingToken               string
+	ErrCodeInvalidToken               string
+	ErrCodeInvalidCookie              string
+	ErrCodeInvalidQueryParameter      string
+	ErrCodeInvalidWebSocketProtocol   string
+	ErrCodeRefreshTokenReused         string
 )
 
 var (
@@ -18,4 +24,9 @@ var (
 	ErrNilCookieRefreshTokenName     = errors.New("cookie refresh token name cannot be nil")
 	ErrNilCookieAccessTokenName      = errors.New("cookie access token name cannot be nil")
 	ErrCodeFailedToSetTokenInContext = errors.New("failed to set token in context")
+	ErrTokenNotFound                 = errors.New("token not found")
+	ErrNilTokenExtractors            = errors.New("at least one token extractor is required")
+	ErrRefreshTokenReused            = errors.New("refresh token has already been used")
+	ErrRefreshInterrupted            = errors.New("token refresh was interrupted")
+	ErrNilRotatedTokenStore          = errors.New("rotated token store cannot be nil")
 )
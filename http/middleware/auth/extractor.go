@@ -0,0 +1,316 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	gojwt "github.com/ralvarezdev/go-jwt"
+
+	gonethttp "github.com/ralvarezdev/go-net/http"
+)
+
+type (
+	// headerExtractor extracts the token from a header with an authentication scheme
+	headerExtractor struct {
+		headerName string
+		scheme     string
+	}
+
+	// cookieExtractor extracts the token from a cookie
+	cookieExtractor struct {
+		cookieName string
+	}
+
+	// queryExtractor extracts the token from a query parameter
+	queryExtractor struct {
+		parameterName string
+	}
+
+	// webSocketProtocolExtractor extracts the token from the Sec-WebSocket-Protocol header
+	webSocketProtocolExtractor struct {
+		prefix string
+	}
+)
+
+// NewHeaderExtractor creates a token extractor that reads the token from a header
+//
+// The scheme is matched case-insensitively and any amount of whitespace is allowed around the scheme and the token.
+//
+// Parameters:
+//
+//   - headerName: The name of the header
+//   - scheme: The authentication scheme (e.g. 'Bearer'), if empty the whole header value is used as the token
+//
+// Returns:
+//
+//   - TokenExtractor: The token extractor
+func NewHeaderExtractor(headerName, scheme string) TokenExtractor {
+	return &headerExtractor{
+		headerName: headerName,
+		scheme:     scheme,
+	}
+}
+
+// NewBearerExtractor creates a token extractor that reads the token from the 'Authorization: Bearer' header
+//
+// Returns:
+//
+//   - TokenExtractor: The token extractor
+func NewBearerExtractor() TokenExtractor {
+	return NewHeaderExtractor(gonethttp.Authorization, gojwt.BearerPrefix)
+}
+
+// Field returns the name of the header
+//
+// Returns:
+//
+//   - string: The name of the header
+func (h headerExtractor) Field() string {
+	return h.headerName
+}
+
+// ErrorCode returns the error code of a malformed header
+//
+// Returns:
+//
+//   - string: The error code
+func (h headerExtractor) ErrorCode() string {
+	return ErrCodeInvalidAuthorizationHeader
+}
+
+// Extract extracts the token from the header
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//
+// Returns:
+//
+//   - string: The raw token
+//   - error: ErrTokenNotFound if the header is missing, ErrInvalidAuthorizationHeader if it's malformed
+func (h headerExtractor) Extract(r *http.Request) (string, error) {
+	value := strings.TrimSpace(r.Header.Get(h.headerName))
+	if value == "" {
+		return "", ErrTokenNotFound
+	}
+
+	// Check if there's no scheme
+	if h.scheme == "" {
+		return value, nil
+	}
+
+	// Check the scheme and the token
+	parts := strings.Fields(value)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], h.scheme) {
+		return "", ErrInvalidAuthorizationHeader
+	}
+	return parts[1], nil
+}
+
+// NewCookieExtractor creates a token extractor that reads the token from a cookie
+//
+// Parameters:
+//
+//   - cookieName: The name of the cookie
+//
+// Returns:
+//
+//   - TokenExtractor: The token extractor
+func NewCookieExtractor(cookieName string) TokenExtractor {
+	return &cookieExtractor{
+		cookieName: cookieName,
+	}
+}
+
+// Field returns the name of the cookie
+//
+// Returns:
+//
+//   - string: The name of the cookie
+func (c cookieExtractor) Field() string {
+	return c.cookieName
+}
+
+// ErrorCode returns the error code of a malformed cookie
+//
+// Returns:
+//
+//   - string: The error code
+func (c cookieExtractor) ErrorCode() string {
+	return ErrCodeInvalidCookie
+}
+
+// Extract extracts the token from the cookie
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//
+// Returns:
+//
+//   - string: The raw token
+//   - error: ErrTokenNotFound if the cookie is missing or empty
+func (c cookieExtractor) Extract(r *http.Request) (string, error) {
+	cookie, err := r.Cookie(c.cookieName)
+	if err != nil {
+		if errors.Is(err, http.ErrNoCookie) {
+			return "", ErrTokenNotFound
+		}
+		return "", err
+	}
+
+	// Check if the cookie value is empty
+	value := strings.TrimSpace(cookie.Value)
+	if value == "" {
+		return "", ErrTokenNotFound
+	}
+	return value, nil
+}
+
+// NewQueryExtractor creates a token extractor that reads the token from a query parameter, meant for WebSocket
+// upgrades where browsers can't set headers
+//
+// Parameters:
+//
+//   - parameterName: The name of the query parameter
+//
+// Returns:
+//
+//   - TokenExtractor: The token extractor
+func NewQueryExtractor(parameterName string) TokenExtractor {
+	return &queryExtractor{
+		parameterName: parameterName,
+	}
+}
+
+// Field returns the name of the query parameter
+//
+// Returns:
+//
+//   - string: The name of the query parameter
+func (q queryExtractor) Field() string {
+	return q.parameterName
+}
+
+// ErrorCode returns the error code of a malformed query parameter
+//
+// Returns:
+//
+//   - string: The error code
+func (q queryExtractor) ErrorCode() string {
+	return ErrCodeInvalidQueryParameter
+}
+
+// Extract extracts the token from the query parameter
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//
+// Returns:
+//
+//   - string: The raw token
+//   - error: ErrTokenNotFound if the query parameter is missing or empty
+func (q queryExtractor) Extract(r *http.Request) (string, error) {
+	value := strings.TrimSpace(r.URL.Query().Get(q.parameterName))
+	if value == "" {
+		return "", ErrTokenNotFound
+	}
+	return value, nil
+}
+
+// NewWebSocketProtocolExtractor creates a token extractor that reads the token from one of the subprotocols offered in
+// the Sec-WebSocket-Protocol header, e.g. 'Sec-WebSocket-Protocol: chat, bearer.<token>'
+//
+// Parameters:
+//
+//   - prefix: The prefix of the subprotocol that carries the token, if empty DefaultWebSocketProtocolPrefix is used
+//
+// Returns:
+//
+//   - TokenExtractor: The token extractor
+func NewWebSocketProtocolExtractor(prefix string) TokenExtractor {
+	if prefix == "" {
+		prefix = DefaultWebSocketProtocolPrefix
+	}
+	return &webSocketProtocolExtractor{
+		prefix: prefix,
+	}
+}
+
+// Field returns the name of the Sec-WebSocket-Protocol header
+//
+// Returns:
+//
+//   - string: The name of the header
+func (w webSocketProtocolExtractor) Field() string {
+	return SecWebSocketProtocol
+}
+
+// ErrorCode returns the error code of a malformed Sec-WebSocket-Protocol header
+//
+// Returns:
+//
+//   - string: The error code
+func (w webSocketProtocolExtractor) ErrorCode() string {
+	return ErrCodeInvalidWebSocketProtocol
+}
+
+// Extract extracts the token from the Sec-WebSocket-Protocol header
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//
+// Returns:
+//
+//   - string: The raw token
+//   - error: ErrTokenNotFound if no subprotocol carries a token
+func (w webSocketProtocolExtractor) Extract(r *http.Request) (string, error) {
+	for _, value := range r.Header.Values(SecWebSocketProtocol) {
+		for _, protocol := range strings.Split(value, ",") {
+			token, ok := strings.CutPrefix(strings.TrimSpace(protocol), w.prefix)
+			if ok && token != "" {
+				return token, nil
+			}
+		}
+	}
+	return "", ErrTokenNotFound
+}
+
+// ExtractToken tries the extractors in order, and returns the token of the first one that finds it
+//
+// An extractor that finds a malformed token stops the chain, so a broken credential is never silently replaced by a
+// lower precedence one.
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//   - extractors: The token extractors, in precedence order
+//
+// Returns:
+//
+//   - string: The raw token
+//   - TokenExtractor: The extractor that found the token or failed, or the first extractor if none found it
+//   - error: The error if any
+func ExtractToken(
+	r *http.Request,
+	extractors ...TokenExtractor,
+) (string, TokenExtractor, error) {
+	// Check if there are no extractors
+	if len(extractors) == 0 {
+		return "", nil, ErrNilTokenExtractors
+	}
+
+	for _, extractor := range extractors {
+		rawToken, err := extractor.Extract(r)
+		if err == nil {
+			return rawToken, extractor, nil
+		}
+		if !errors.Is(err, ErrTokenNotFound) {
+			return "", extractor, err
+		}
+	}
+	return "", extractors[0], ErrTokenNotFound
+}
@@ -9,6 +9,7 @@ var (
 )
 
 var (
-	ErrNilInterceptions     = errors.New("nil interceptions")
-	ErrInterceptionNotFound = errors.New("interception not found")
+	ErrNilInterceptions       = errors.New("nil interceptions")
+	ErrInterceptionNotFound   = errors.New("interception not found")
+	ErrExtractorsNotSupported = errors.New("authenticator does not support token extractors")
 )
@@ -2,6 +2,8 @@ package grpc
 
 import (
 	"net/http"
+
+	gonethttpmiddlewareauth "github.com/ralvarezdev/go-net/http/middleware/auth"
 )
 
 type (
@@ -14,4 +16,21 @@ type (
 			rpcMethod string,
 		) func(next http.Handler) http.Handler
 	}
+
+	// ExtractorsAuthenticator is the optional interface of the authenticators that get the token from a chain of
+	// token extractors
+	ExtractorsAuthenticator interface {
+		AuthenticateFromExtractors(
+			rpcMethod string,
+			extractors ...gonethttpmiddlewareauth.TokenExtractor,
+		) func(next http.Handler) http.Handler
+	}
+
+	// MetadataAuthenticator is the optional interface of the authenticators that get the RPC method from the route
+	// metadata
+	MetadataAuthenticator interface {
+		AuthenticateFromMetadata(
+			extractors ...gonethttpmiddlewareauth.TokenExtractor,
+		) func(next http.Handler) http.Handler
+	}
 )
@@ -9,6 +9,7 @@ import (
 	gonethttp "github.com/ralvarezdev/go-net/http"
 	gonethttphandler "github.com/ralvarezdev/go-net/http/handler"
 	gonethttpmiddlewareauth "github.com/ralvarezdev/go-net/http/middleware/auth"
+	gonethttproute "github.com/ralvarezdev/go-net/http/route"
 )
 
 type (
@@ -181,3 +182,100 @@ func (m Middleware) AuthenticateFromCookie(
 		)
 	}
 }
+
+// AuthenticateFromExtractors is a middleware function that authenticates requests based on the provided RPC method
+// using the token found by the first extractor of the chain that finds it.
+//
+// Parameters:
+//
+//   - rpcMethod: The RPC method to authenticate against.
+//   - extractors: The token extractors, in precedence order.
+//
+// Returns:
+//
+//   - func(next http.Handler) http.Handler: A middleware function that authenticates requests. It panics if the
+//     RPC method needs authentication and the authenticator doesn't implement the ExtractorsAuthenticator interface.
+func (m Middleware) AuthenticateFromExtractors(
+	rpcMethod string,
+	extractors ...gonethttpmiddlewareauth.TokenExtractor,
+) func(next http.Handler) http.Handler {
+	// Try to find the interception for the given RPC method
+	token, ok := m.interceptions[rpcMethod]
+	if !ok {
+		return m.interceptionNotFoundHandler(
+			rpcMethod,
+		)
+	}
+
+	// Check if the authentication is needed
+	if token != nil {
+		// Check if the authenticator supports the token extractors
+		authenticator, ok := m.authenticator.(gonethttpmiddlewareauth.ExtractorsAuthenticator)
+		if !ok {
+			panic(ErrExtractorsNotSupported)
+		}
+		return authenticator.AuthenticateFromExtractors(
+			*token,
+			extractors...,
+		)
+	}
+
+	// If no authentication is needed, return a no-op middleware
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				next.ServeHTTP(w, r)
+			},
+		)
+	}
+}
+
+// AuthenticateFromMetadata is a middleware function that authenticates requests based on the RPC method of the
+// matched route metadata, so it can be applied once to a whole module. The chain of each intercepted RPC method is
+// built once, when the middleware is applied.
+//
+// Parameters:
+//
+//   - extractors: The token extractors, in precedence order. If empty, the bearer token of the Authorization header
+//     is used.
+//
+// Returns:
+//
+//   - func(next http.Handler) http.Handler: A middleware function that authenticates requests.
+func (m Middleware) AuthenticateFromMetadata(
+	extractors ...gonethttpmiddlewareauth.TokenExtractor,
+) func(next http.Handler) http.Handler {
+	// Set the default extractor
+	if len(extractors) == 0 {
+		extractors = []gonethttpmiddlewareauth.TokenExtractor{gonethttpmiddlewareauth.NewBearerExtractor()}
+	}
+
+	return func(next http.Handler) http.Handler {
+		// Build the chain of each intercepted RPC method
+		handlers := make(map[string]http.Handler, len(m.interceptions))
+		for rpcMethod := range m.interceptions {
+			handlers[rpcMethod] = m.AuthenticateFromExtractors(
+				rpcMethod,
+				extractors...,
+			)(next)
+		}
+
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				// Get the RPC method from the route metadata
+				var rpcMethod string
+				if metadata := gonethttproute.GetCtxRouteMetadata(r); metadata != nil {
+					rpcMethod = metadata.RPCMethod
+				}
+
+				// Authenticate the request with the interception of the RPC method, if it's not set or not found the
+				// interception is not found
+				handler, ok := handlers[rpcMethod]
+				if !ok {
+					handler = m.interceptionNotFoundHandler(rpcMethod)(next)
+				}
+				handler.ServeHTTP(w, r)
+			},
+		)
+	}
+}
@@ -1,7 +1,9 @@
 package auth
 
 import (
+	"context"
 	"net/http"
+	"time"
 
 	gojwttoken "github.com/ralvarezdev/go-jwt/token"
 )
@@ -16,4 +18,37 @@ type (
 			token gojwttoken.Token,
 		) func(next http.Handler) http.Handler
 	}
+
+	// ExtractorsAuthenticator is the optional interface of the authenticators that get the token from a chain of
+	// token extractors
+	ExtractorsAuthenticator interface {
+		AuthenticateFromExtractors(
+			token gojwttoken.Token,
+			extractors ...TokenExtractor,
+		) func(next http.Handler) http.Handler
+	}
+
+	// RotatedTokenStore is the interface for the storage of the rotated refresh tokens, used to detect their replay.
+	// A shared store, e.g. Redis, detects the replay across instances, but the refresh results are only shared with the
+	// requests served by the same instance, so a concurrent refresh on another instance is detected as a replay
+	RotatedTokenStore interface {
+		// MarkRotated atomically marks a refresh token hash as rotated for the given TTL. It returns false if it was
+		// already marked
+		MarkRotated(ctx context.Context, tokenHash string, ttl time.Duration) (bool, error)
+
+		// UnmarkRotated removes the rotated mark of a refresh token hash whose refresh failed, so it can be retried
+		UnmarkRotated(ctx context.Context, tokenHash string) error
+	}
+
+	// TokenExtractor is the interface to extract a raw token from a request
+	TokenExtractor interface {
+		Field() string
+		Extract(r *http.Request) (string, error)
+	}
+
+	// TokenExtractorErrorCoder is the optional interface of the token extractors that report their own error code
+	// when the token they find is malformed
+	TokenExtractorErrorCoder interface {
+		ErrorCode() string
+	}
 )
@@ -3,14 +3,14 @@ package auth
 import (
 	"errors"
 	"net/http"
-	"strings"
+	"time"
 
-	gojwt "github.com/ralvarezdev/go-jwt"
 	gojwtnethttp "github.com/ralvarezdev/go-jwt/net/http"
 	gojwttoken "github.com/ralvarezdev/go-jwt/token"
 	gojwtvalidator "github.com/ralvarezdev/go-jwt/token/validator"
 
 	gonethttp "github.com/ralvarezdev/go-net/http"
+	gonethttpcookie "github.com/ralvarezdev/go-net/http/cookie"
 	gonethttphandler "github.com/ralvarezdev/go-net/http/handler"
 )
 
@@ -20,6 +20,7 @@ type (
 		validator        gojwtvalidator.Validator
 		responsesHandler gonethttphandler.ResponsesHandler
 		options          *Options
+		refresher        *refreshDeduplicator
 	}
 
 	// Options is the options for the authentication middleware
@@ -27,6 +28,22 @@ type (
 		CookieRefreshTokenName *string
 		CookieAccessTokenName  *string
 		RefreshTokenFn         RefreshTokenFn
+
+		// CookieAccessTokenAttributes and CookieRefreshTokenAttributes are the attributes of the cookies written with
+		// the refreshed tokens. If set, the middleware writes the cookies for every request that shares the refresh
+		// result, so the RefreshTokenFn must not write them
+		CookieAccessTokenAttributes  *gonethttpcookie.Attributes
+		CookieRefreshTokenAttributes *gonethttpcookie.Attributes
+
+		// RefreshResultTTL is the time a refresh result is shared with the requests that carry the same refresh token
+		RefreshResultTTL time.Duration
+
+		// RefreshTokenReusedFn is called when a rotated refresh token is presented again (can be nil)
+		RefreshTokenReusedFn RefreshTokenReusedFn
+
+		// RotatedTokenStore is the store of the rotated refresh tokens, used to detect their replay. If nil, an
+		// in-memory store is used, which only detects the replay on the same instance
+		RotatedTokenStore RotatedTokenStore
 	}
 )
 
@@ -47,10 +64,45 @@ func NewOptions(
 	refreshTokenFn RefreshTokenFn,
 ) *Options {
 	return &Options{
-		cookieRefreshTokenName,
-		cookieAccessTokenName,
-		refreshTokenFn,
+		CookieRefreshTokenName: cookieRefreshTokenName,
+		CookieAccessTokenName:  cookieAccessTokenName,
+		RefreshTokenFn:         refreshTokenFn,
+	}
+}
+
+// NewCookieRotationOptions creates a new Options struct that writes the refreshed tokens cookies with the given
+// attributes
+//
+// Parameters:
+//
+//   - cookieRefreshTokenAttributes: The attributes of the cookie that contains the refresh token
+//   - cookieAccessTokenAttributes: The attributes of the cookie that contains the access token
+//   - refreshTokenFn: The function to refresh the tokens using the refresh token, it must not write the cookies
+//   - refreshTokenReusedFn: The function called when a rotated refresh token is presented again (can be nil)
+//
+// Returns:
+//
+//   - *Options: The options for the authentication middleware
+func NewCookieRotationOptions(
+	cookieRefreshTokenAttributes,
+	cookieAccessTokenAttributes *gonethttpcookie.Attributes,
+	refreshTokenFn RefreshTokenFn,
+	refreshTokenReusedFn RefreshTokenReusedFn,
+) *Options {
+	options := &Options{
+		RefreshTokenFn:               refreshTokenFn,
+		CookieAccessTokenAttributes:  cookieAccessTokenAttributes,
+		CookieRefreshTokenAttributes: cookieRefreshTokenAttributes,
+		RefreshResultTTL:             DefaultRefreshResultTTL,
+		RefreshTokenReusedFn:         refreshTokenReusedFn,
+	}
+	if cookieRefreshTokenAttributes != nil {
+		options.CookieRefreshTokenName = &cookieRefreshTokenAttributes.Name
 	}
+	if cookieAccessTokenAttributes != nil {
+		options.CookieAccessTokenName = &cookieAccessTokenAttributes.Name
+	}
+	return options
 }
 
 // NewMiddleware creates a new authentication middleware
@@ -77,13 +129,70 @@ func NewMiddleware(
 		return nil, gojwtvalidator.ErrNilValidator
 	}
 
+	// Create the refresh deduplicator
+	var refreshResultTTL time.Duration
+	var rotatedTokenStore RotatedTokenStore
+	if options != nil {
+		refreshResultTTL = options.RefreshResultTTL
+		rotatedTokenStore = options.RotatedTokenStore
+	}
+
 	return &Middleware{
 		validator,
 		responsesHandler,
 		options,
+		newRefreshDeduplicator(refreshResultTTL, rotatedTokenStore),
 	}, nil
 }
 
+// expiresAt returns the expiration time of a raw token from its claims
+//
+// Parameters:
+//
+//   - rawToken: The raw JWT token string
+//
+// Returns:
+//
+//   - time.Time: The expiration time, or the zero time if the token has no expiration claim
+func (m Middleware) expiresAt(rawToken string) time.Time {
+	claims, err := m.validator.GetClaims(rawToken)
+	if err != nil {
+		return time.Time{}
+	}
+	expirationTime, err := claims.GetExpirationTime()
+	if err != nil || expirationTime == nil {
+		return time.Time{}
+	}
+	return expirationTime.Time
+}
+
+// setRefreshedCookies writes the cookies of the refreshed tokens, if their attributes are set
+//
+// Parameters:
+//
+//   - w: The HTTP response writer
+//   - rawTokens: The refreshed raw tokens
+func (m Middleware) setRefreshedCookies(
+	w http.ResponseWriter,
+	rawTokens map[gojwttoken.Token]string,
+) {
+	for token, attributes := range map[gojwttoken.Token]*gonethttpcookie.Attributes{
+		gojwttoken.AccessToken:  m.options.CookieAccessTokenAttributes,
+		gojwttoken.RefreshToken: m.options.CookieRefreshTokenAttributes,
+	} {
+		rawToken, ok := rawTokens[token]
+		if attributes == nil || !ok || rawToken == "" {
+			continue
+		}
+		gonethttpcookie.SetCookie(
+			w,
+			attributes,
+			rawToken,
+			m.expiresAt(rawToken),
+		)
+	}
+}
+
 // authenticate return the middleware function that authenticates the request
 //
 // Parameters:
@@ -186,34 +295,117 @@ func (m Middleware) authenticateFromHeaderFailHandler(
 func (m Middleware) AuthenticateFromHeader(
 	token gojwttoken.Token,
 ) func(next http.Handler) http.Handler {
+	return m.AuthenticateFromExtractors(token, NewBearerExtractor())
+}
+
+// extractorFailHandler is the default fail handler for AuthenticateFromExtractors
+//
+// Parameters:
+//
+//   - extractor: The extractor that found the token, or failed to
+//
+// Returns:
+//
+//   - FailHandlerFn: The fail handler function
+func (m Middleware) extractorFailHandler(
+	extractor TokenExtractor,
+) FailHandlerFn {
+	// Use the authorization header fail handler for the default extractor
+	if extractor.Field() == gonethttp.Authorization {
+		return m.authenticateFromHeaderFailHandler
+	}
+
+	return func(
+		w http.ResponseWriter,
+		r *http.Request,
+		err error,
+		errorCode string,
+	) {
+		m.responsesHandler.HandleFailFieldErrorWithCode(
+			w,
+			r,
+			extractor.Field(),
+			err,
+			errorCode,
+			http.StatusUnauthorized,
+		)
+	}
+}
+
+// extractorErrorCode returns the error code of a malformed token found by an extractor
+//
+// Parameters:
+//
+//   - extractor: The extractor that found the malformed token
+//
+// Returns:
+//
+//   - string: The error code of the extractor, or ErrCodeInvalidToken if it doesn't report one
+func extractorErrorCode(extractor TokenExtractor) string {
+	if errorCoder, ok := extractor.(TokenExtractorErrorCoder); ok {
+		return errorCoder.ErrorCode()
+	}
+	return ErrCodeInvalidToken
+}
+
+// AuthenticateFromExtractors return the middleware function that authenticates the request with the token found by
+// the first extractor of the chain that finds it
+//
+// Parameters:
+//
+//   - token: The type of token to authenticate (access or refresh)
+//   - extractors: The token extractors, in precedence order
+//
+// Returns:
+//
+//   - func(next http.Handler) http.Handler: The middleware function
+func (m Middleware) AuthenticateFromExtractors(
+	token gojwttoken.Token,
+	extractors ...TokenExtractor,
+) func(next http.Handler) http.Handler {
+	// Check if there are no extractors
+	if len(extractors) == 0 {
+		panic(ErrNilTokenExtractors)
+	}
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(
 			func(w http.ResponseWriter, r *http.Request) {
-				// Get the authorization from the header
-				authorization := r.Header.Get(gonethttp.Authorization)
-
-				// Check if the authorization is a bearer token
-				parts := strings.Split(authorization, " ")
-
-				// Return an error if the authorization is missing or invalid
-				if len(parts) < 2 || parts[0] != gojwt.BearerPrefix {
-					m.authenticateFromHeaderFailHandler(
-						w,
-						r,
-						ErrInvalidAuthorizationHeader,
-						ErrCodeInvalidAuthorizationHeader,
-					)
+				// Extract the raw token from the request
+				rawToken, extractor, err := ExtractToken(r, extractors...)
+				failHandler := m.extractorFailHandler(extractor)
+				if err != nil {
+					switch {
+					case errors.Is(err, ErrTokenNotFound) && extractor.Field() == gonethttp.Authorization:
+						failHandler(
+							w,
+							r,
+							ErrInvalidAuthorizationHeader,
+							ErrCodeInvalidAuthorizationHeader,
+						)
+					case errors.Is(err, ErrTokenNotFound):
+						failHandler(
+							w,
+							r,
+							err,
+							ErrCodeMissingToken,
+						)
+					default:
+						failHandler(
+							w,
+							r,
+							err,
+							extractorErrorCode(extractor),
+						)
+					}
 					return
 				}
 
-				// Get the raw token from the header
-				rawToken := parts[1]
-
 				// Call the authenticate function
 				m.authenticate(
 					token,
 					rawToken,
-					m.authenticateFromHeaderFailHandler,
+					failHandler,
 				)(next).ServeHTTP(
 					w,
 					r,
@@ -331,7 +523,8 @@ func (m Middleware) AuthenticateFromCookie(
 					}
 
 					// Check if the refresh token cookie is present
-					if _, err = r.Cookie(refreshTokenCookieName); err != nil {
+					var refreshTokenCookie *http.Cookie
+					if refreshTokenCookie, err = r.Cookie(refreshTokenCookieName); err != nil {
 						currentFailHandler(
 							w,
 							r,
@@ -341,8 +534,39 @@ func (m Middleware) AuthenticateFromCookie(
 						return
 					}
 
-					// Refresh the token
-					rawTokens, refreshErr := m.options.RefreshTokenFn(w, r)
+					// Refresh the token, once for all the concurrent requests that carry the same refresh token
+					rotatedUntil := m.expiresAt(refreshTokenCookie.Value)
+					if rotatedUntil.IsZero() {
+						rotatedUntil = time.Now().Add(DefaultRotatedTokenTTL)
+					}
+					rawTokens, refreshErr := m.refresher.refresh(
+						w,
+						r,
+						refreshTokenCookie.Value,
+						rotatedUntil,
+						m.options.RefreshTokenFn,
+					)
+					if errors.Is(refreshErr, ErrRefreshTokenReused) {
+						// Report the replay, and remove the cookies of the compromised session
+						if m.options.RefreshTokenReusedFn != nil {
+							m.options.RefreshTokenReusedFn(r, refreshTokenCookie.Value)
+						}
+						for _, attributes := range []*gonethttpcookie.Attributes{
+							m.options.CookieAccessTokenAttributes,
+							m.options.CookieRefreshTokenAttributes,
+						} {
+							if attributes != nil {
+								gonethttpcookie.DeleteCookie(w, attributes)
+							}
+						}
+						refreshTokenFailHandler(
+							w,
+							r,
+							refreshErr,
+							ErrCodeRefreshTokenReused,
+						)
+						return
+					}
 					if refreshErr != nil {
 						refreshTokenFailHandler(
 							w,
@@ -353,6 +577,9 @@ func (m Middleware) AuthenticateFromCookie(
 						return
 					}
 
+					// Write the refreshed tokens cookies
+					m.setRefreshedCookies(w, rawTokens)
+
 					// Get the raw token from the map, if not found set it to an empty string
 					if rawToken, ok = rawTokens[token]; !ok {
 						rawToken = ""
@@ -0,0 +1,7 @@
+package mtls
+
+const (
+	// ClientCertificateField is the field of the failed authentication responses, since the client certificate is
+	// not sent in a header
+	ClientCertificateField = "client_certificate"
+)
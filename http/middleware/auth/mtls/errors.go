@@ -0,0 +1,28 @@
+package mtls
+
+import (
+	"errors"
+)
+
+var (
+	ErrCodeMissingClientCertificate string
+	ErrCodeUnknownClientIdentity    string
+	ErrCodeClientNotAllowed         string
+)
+
+const (
+	ErrNoCACertificates = "no CA certificates found in file: %s"
+)
+
+var (
+	ErrNilOptions                = errors.New("mtls options cannot be nil")
+	ErrNilTLSOptions             = errors.New("tls options cannot be nil")
+	ErrNilCAReloader             = errors.New("ca reloader cannot be nil")
+	ErrEmptyClientCAFile         = errors.New("client ca file cannot be empty")
+	ErrUnknownIdentitySource     = errors.New("unknown identity source")
+	ErrInvalidAllowedPattern     = errors.New("invalid allowed principal pattern")
+	ErrMissingClientCertificate  = errors.New("missing verified client certificate")
+	ErrUnknownClientIdentity     = errors.New("unknown client certificate identity")
+	ErrClientNotAllowed          = errors.New("client is not allowed to access this resource")
+	ErrMissingServerCertificates = errors.New("server certificate and key files cannot be empty")
+)
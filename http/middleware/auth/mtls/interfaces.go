@@ -0,0 +1,12 @@
+package mtls
+
+import (
+	"net/http"
+)
+
+type (
+	// Authenticator interface
+	Authenticator interface {
+		Authenticate(allowed ...string) func(next http.Handler) http.Handler
+	}
+)
@@ -0,0 +1,206 @@
+package mtls
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"path"
+
+	gonethttpctx "github.com/ralvarezdev/go-net/http/context"
+	gonethttphandler "github.com/ralvarezdev/go-net/http/handler"
+)
+
+type (
+	// Middleware struct is the mTLS client certificate authentication middleware
+	Middleware struct {
+		responsesHandler gonethttphandler.ResponsesHandler
+		options          *Options
+		logger           *slog.Logger
+	}
+
+	// Options is the options for the mTLS client certificate authentication middleware
+	Options struct {
+		// Source is the part of the client certificate used as its identity
+		Source IdentitySource
+
+		// Principals maps the certificate identities to principals, if nil the identity is used as the principal
+		Principals map[string]string
+	}
+)
+
+// NewOptions creates a new Options struct
+//
+// Parameters:
+//
+//   - source: The part of the client certificate used as its identity
+//   - principals: The mapping from certificate identities to principals (can be nil)
+//
+// Returns:
+//
+//   - *Options: The options for the mTLS client certificate authentication middleware
+func NewOptions(
+	source IdentitySource,
+	principals map[string]string,
+) *Options {
+	return &Options{
+		source,
+		principals,
+	}
+}
+
+// NewMiddleware creates a new mTLS client certificate authentication middleware
+//
+// Parameters:
+//
+//   - responsesHandler: The HTTP handler to handle errors
+//   - options: The options for the middleware
+//   - logger: The logger (can be nil)
+//
+// Returns:
+//
+//   - *Middleware: The mTLS client certificate authentication middleware
+//   - error: The error if any
+func NewMiddleware(
+	responsesHandler gonethttphandler.ResponsesHandler,
+	options *Options,
+	logger *slog.Logger,
+) (*Middleware, error) {
+	// Check if the responses handler or the options are nil
+	if responsesHandler == nil {
+		return nil, gonethttphandler.ErrNilHandler
+	}
+	if options == nil {
+		return nil, ErrNilOptions
+	}
+
+	// Check the identity source
+	if options.Source < IdentitySubject || options.Source > IdentityFingerprint {
+		return nil, ErrUnknownIdentitySource
+	}
+
+	if logger != nil {
+		logger = logger.With(
+			slog.String("component", "http_middleware_auth_mtls"),
+		)
+	}
+
+	return &Middleware{
+		responsesHandler,
+		options,
+		logger,
+	}, nil
+}
+
+// principal maps the verified peer certificate of the request to a principal
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//
+// Returns:
+//
+//   - string: The principal
+//   - error: The error if any
+func (m Middleware) principal(r *http.Request) (string, error) {
+	// Get the verified peer certificate, the leaf of the first verified chain
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return "", ErrMissingClientCertificate
+	}
+	certificate := r.TLS.VerifiedChains[0][0]
+
+	// Get the certificate identity
+	identity := m.options.Source.Identity(certificate)
+	if identity == "" {
+		return "", ErrUnknownClientIdentity
+	}
+
+	// Map the identity to a principal
+	if m.options.Principals == nil {
+		return identity, nil
+	}
+	principal, ok := m.options.Principals[identity]
+	if !ok {
+		return "", ErrUnknownClientIdentity
+	}
+	return principal, nil
+}
+
+// Authenticate return the middleware function that authenticates the request with the verified client certificate
+//
+// Parameters:
+//
+//   - allowed: The principals allowed to access the route, as path.Match patterns (e.g.
+//     'spiffe://example.org/ns/prod/*'). If empty, every authenticated principal is allowed
+//
+// Returns:
+//
+//   - func(next http.Handler) http.Handler: The middleware function
+func (m Middleware) Authenticate(
+	allowed ...string,
+) func(next http.Handler) http.Handler {
+	// Check the allowed patterns
+	for _, pattern := range allowed {
+		if _, err := path.Match(pattern, ""); err != nil {
+			panic(ErrInvalidAllowedPattern)
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				// Get the principal from the client certificate
+				principal, err := m.principal(r)
+				if err != nil {
+					errorCode := ErrCodeUnknownClientIdentity
+					if errors.Is(err, ErrMissingClientCertificate) {
+						errorCode = ErrCodeMissingClientCertificate
+					}
+					m.responsesHandler.HandleFailFieldErrorWithCode(
+						w,
+						r,
+						ClientCertificateField,
+						err,
+						errorCode,
+						http.StatusUnauthorized,
+					)
+					return
+				}
+
+				// Check the principal against the allow-list
+				if len(allowed) > 0 {
+					isAllowed := false
+					for _, pattern := range allowed {
+						if matched, _ := path.Match(pattern, principal); matched {
+							isAllowed = true
+							break
+						}
+					}
+					if !isAllowed {
+						if m.logger != nil {
+							m.logger.Warn(
+								"Client certificate principal not allowed",
+								slog.String("principal", principal),
+								slog.String("path", r.URL.Path),
+							)
+						}
+						m.responsesHandler.HandleFailFieldErrorWithCode(
+							w,
+							r,
+							ClientCertificateField,
+							ErrClientNotAllowed,
+							ErrCodeClientNotAllowed,
+							http.StatusForbidden,
+						)
+						return
+					}
+				}
+
+				// Set the principal to the context
+				r = gonethttpctx.SetCtxPrincipal(r, principal)
+
+				// Call the next handler
+				next.ServeHTTP(w, r)
+			},
+		)
+	}
+}
@@ -0,0 +1,229 @@
+package mtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+type (
+	// TLSOptions is the options to build the server TLS configuration
+	TLSOptions struct {
+		// CertFile is the server certificate file
+		CertFile string
+
+		// KeyFile is the server private key file
+		KeyFile string
+
+		// ClientCAFile is the PEM bundle of the CAs used to verify the client certificates
+		ClientCAFile string
+
+		// ClientAuth is the client authentication policy, defaults to tls.RequireAndVerifyClientCert
+		ClientAuth tls.ClientAuthType
+
+		// ReloadInterval is the minimum interval between checks of the CA bundle file for changes, if zero the bundle
+		// is only reloaded on explicit calls to CAReloader.Reload
+		ReloadInterval time.Duration
+	}
+
+	// CAReloader keeps the client CA pool loaded from a PEM bundle, and reloads it when the file changes
+	CAReloader struct {
+		file           string
+		reloadInterval time.Duration
+		pool           *x509.CertPool
+		modTime        time.Time
+		checkedAt      time.Time
+		mutex          sync.RWMutex
+		logger         *slog.Logger
+	}
+)
+
+// NewCAReloader creates a new CA bundle reloader, loading the bundle for the first time
+//
+// Parameters:
+//
+//   - file: The PEM bundle file
+//   - reloadInterval: The minimum interval between checks of the file for changes
+//   - logger: The logger (can be nil)
+//
+// Returns:
+//
+//   - *CAReloader: The CA bundle reloader
+//   - error: The error if any
+func NewCAReloader(
+	file string,
+	reloadInterval time.Duration,
+	logger *slog.Logger,
+) (*CAReloader, error) {
+	// Check the file
+	if file == "" {
+		return nil, ErrEmptyClientCAFile
+	}
+
+	if logger != nil {
+		logger = logger.With(
+			slog.String("component", "http_middleware_auth_mtls_ca_reloader"),
+		)
+	}
+
+	reloader := &CAReloader{
+		file:           file,
+		reloadInterval: reloadInterval,
+		logger:         logger,
+	}
+	if err := reloader.Reload(); err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
+
+// Reload reloads the CA bundle from its file
+//
+// Returns:
+//
+//   - error: The error if any, in which case the previous pool is kept
+func (c *CAReloader) Reload() error {
+	if c == nil {
+		return ErrNilCAReloader
+	}
+
+	// Read the file
+	info, err := os.Stat(c.file)
+	if err != nil {
+		return err
+	}
+	bundle, err := os.ReadFile(c.file)
+	if err != nil {
+		return err
+	}
+
+	// Parse the bundle
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(bundle) {
+		return fmt.Errorf(ErrNoCACertificates, c.file)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.pool = pool
+	c.modTime = info.ModTime()
+	c.checkedAt = time.Now()
+	return nil
+}
+
+// Pool returns the current CA pool, reloading the bundle first if the file has changed since the last check
+//
+// Returns:
+//
+//   - *x509.CertPool: The CA pool
+func (c *CAReloader) Pool() *x509.CertPool {
+	if c == nil {
+		return nil
+	}
+
+	// Check if the file must be checked for changes
+	c.mutex.RLock()
+	pool, modTime, checkedAt := c.pool, c.modTime, c.checkedAt
+	c.mutex.RUnlock()
+	if c.reloadInterval <= 0 || time.Since(checkedAt) < c.reloadInterval {
+		return pool
+	}
+
+	// Reload the bundle if the file has changed
+	info, err := os.Stat(c.file)
+	if err == nil && info.ModTime().Equal(modTime) {
+		c.mutex.Lock()
+		c.checkedAt = time.Now()
+		c.mutex.Unlock()
+		return pool
+	}
+	if err == nil {
+		err = c.Reload()
+	}
+	if err != nil {
+		// Keep the previous pool, and wait for the next interval to retry
+		if c.logger != nil {
+			c.logger.Error(
+				"Failed to reload client CA bundle",
+				slog.String("file", c.file),
+				slog.Any("error", err),
+			)
+		}
+		c.mutex.Lock()
+		c.checkedAt = time.Now()
+		c.mutex.Unlock()
+		return pool
+	}
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.pool
+}
+
+// NewServerTLSConfig creates the server TLS configuration to require and verify client certificates, reloading the
+// client CA bundle on handshakes when it changes
+//
+// Parameters:
+//
+//   - options: The TLS options
+//   - logger: The logger (can be nil)
+//
+// Returns:
+//
+//   - *tls.Config: The server TLS configuration
+//   - *CAReloader: The CA bundle reloader, to force reloads
+//   - error: The error if any
+func NewServerTLSConfig(
+	options *TLSOptions,
+	logger *slog.Logger,
+) (*tls.Config, *CAReloader, error) {
+	// Check the options
+	if options == nil {
+		return nil, nil, ErrNilTLSOptions
+	}
+	if options.CertFile == "" || options.KeyFile == "" {
+		return nil, nil, ErrMissingServerCertificates
+	}
+
+	// Load the server certificate
+	certificate, err := tls.LoadX509KeyPair(options.CertFile, options.KeyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Load the client CA bundle
+	reloader, err := NewCAReloader(
+		options.ClientCAFile,
+		options.ReloadInterval,
+		logger,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Set the client authentication policy
+	clientAuth := options.ClientAuth
+	if clientAuth == tls.NoClientCert {
+		clientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{certificate},
+		ClientAuth:   clientAuth,
+		ClientCAs:    reloader.Pool(),
+	}
+
+	// Use the current CA pool on every handshake
+	config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		clientConfig := config.Clone()
+		clientConfig.GetConfigForClient = nil
+		clientConfig.ClientCAs = reloader.Pool()
+		return clientConfig, nil
+	}
+	return config, reloader, nil
+}
@@ -0,0 +1,69 @@
+package mtls
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+)
+
+type (
+	// IdentitySource is the part of the client certificate used as its identity
+	IdentitySource int
+)
+
+const (
+	// IdentitySubject uses the certificate subject common name
+	IdentitySubject IdentitySource = iota
+
+	// IdentityURISAN uses the first SAN URI, e.g. a SPIFFE ID like 'spiffe://example.org/ns/prod/sa/billing'
+	IdentityURISAN
+
+	// IdentityFingerprint uses the lowercase hex SHA-256 fingerprint of the certificate
+	IdentityFingerprint
+)
+
+// Fingerprint returns the lowercase hex SHA-256 fingerprint of a certificate
+//
+// Parameters:
+//
+//   - certificate: The certificate
+//
+// Returns:
+//
+//   - string: The fingerprint
+func Fingerprint(certificate *x509.Certificate) string {
+	if certificate == nil {
+		return ""
+	}
+	hash := sha256.Sum256(certificate.Raw)
+	return hex.EncodeToString(hash[:])
+}
+
+// Identity returns the identity of a certificate for the given source
+//
+// Parameters:
+//
+//   - certificate: The certificate
+//
+// Returns:
+//
+//   - string: The identity, or an empty string if the certificate has none for this source
+func (i IdentitySource) Identity(certificate *x509.Certificate) string {
+	if certificate == nil {
+		return ""
+	}
+
+	switch i {
+	case IdentitySubject:
+		return certificate.Subject.CommonName
+	case IdentityURISAN:
+		if len(certificate.URIs) == 0 {
+			return ""
+		}
+		return certificate.URIs[0].String()
+	case IdentityFingerprint:
+		return Fingerprint(certificate)
+	default:
+		return ""
+	}
+}
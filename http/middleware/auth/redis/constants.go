@@ -0,0 +1,6 @@
+package redis
+
+const (
+	// DefaultPrefix is the default prefix of the rotated refresh token hashes stored in Redis
+	DefaultPrefix = "rotated_refresh_token:"
+)
@@ -0,0 +1,9 @@
+package redis
+
+import (
+	"errors"
+)
+
+var (
+	ErrNilClient = errors.New("redis client cannot be nil")
+)
@@ -0,0 +1,84 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	gonethttpmiddlewareauth "github.com/ralvarezdev/go-net/http/middleware/auth"
+)
+
+type (
+	// RotatedTokenStore is the Redis implementation of the auth RotatedTokenStore interface, so the replay of the
+	// rotated refresh tokens is detected across instances, and Redis expires them
+	RotatedTokenStore struct {
+		client goredis.UniversalClient
+		prefix string
+	}
+)
+
+// NewRotatedTokenStore creates a new Redis rotated token store
+//
+// Parameters:
+//
+//   - client: The Redis client
+//   - prefix: The prefix of the stored keys, DefaultPrefix if empty
+//
+// Returns:
+//
+//   - *RotatedTokenStore: The Redis rotated token store
+//   - error: The error if any
+func NewRotatedTokenStore(client goredis.UniversalClient, prefix string) (*RotatedTokenStore, error) {
+	if client == nil {
+		return nil, ErrNilClient
+	}
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+
+	return &RotatedTokenStore{
+		client: client,
+		prefix: prefix,
+	}, nil
+}
+
+// MarkRotated atomically marks a refresh token hash as rotated for the given TTL
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - tokenHash: The refresh token hash
+//   - ttl: The time the replay of the refresh token is detected
+//
+// Returns:
+//
+//   - bool: True if it was marked, false if it was already marked
+//   - error: The error if any
+func (s *RotatedTokenStore) MarkRotated(
+	ctx context.Context,
+	tokenHash string,
+	ttl time.Duration,
+) (bool, error) {
+	if s == nil {
+		return false, gonethttpmiddlewareauth.ErrNilRotatedTokenStore
+	}
+	return s.client.SetNX(ctx, s.prefix+tokenHash, 1, ttl).Result()
+}
+
+// UnmarkRotated removes the rotated mark of a refresh token hash whose refresh failed, so it can be retried
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - tokenHash: The refresh token hash
+//
+// Returns:
+//
+//   - error: The error if any
+func (s *RotatedTokenStore) UnmarkRotated(ctx context.Context, tokenHash string) error {
+	if s == nil {
+		return gonethttpmiddlewareauth.ErrNilRotatedTokenStore
+	}
+	return s.client.Del(ctx, s.prefix+tokenHash).Err()
+}
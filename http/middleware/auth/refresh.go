@@ -0,0 +1,166 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	gojwttoken "github.com/ralvarezdev/go-jwt/token"
+)
+
+type (
+	// refreshCall is an in-flight or recently completed token refresh
+	refreshCall struct {
+		done      chan struct{}
+		rawTokens map[gojwttoken.Token]string
+		err       error
+		expiresAt time.Time
+	}
+
+	// refreshDeduplicator deduplicates the concurrent refreshes of the same refresh token, and detects the replay of
+	// refresh tokens that were already rotated
+	refreshDeduplicator struct {
+		calls     map[string]*refreshCall
+		rotated   RotatedTokenStore
+		resultTTL time.Duration
+		mutex     sync.Mutex
+	}
+)
+
+// newRefreshDeduplicator creates a new refresh deduplicator
+//
+// Parameters:
+//
+//   - resultTTL: The time a completed refresh result is shared with the requests that carry the same refresh token
+//   - rotated: The store of the rotated refresh tokens, if nil an in-memory store is used
+//
+// Returns:
+//
+//   - *refreshDeduplicator: The refresh deduplicator
+func newRefreshDeduplicator(
+	resultTTL time.Duration,
+	rotated RotatedTokenStore,
+) *refreshDeduplicator {
+	if resultTTL <= 0 {
+		resultTTL = DefaultRefreshResultTTL
+	}
+	if rotated == nil {
+		rotated = NewMemoryRotatedTokenStore()
+	}
+	return &refreshDeduplicator{
+		calls:     make(map[string]*refreshCall),
+		rotated:   rotated,
+		resultTTL: resultTTL,
+	}
+}
+
+// hashToken hashes a raw token, to avoid keeping raw refresh tokens in memory
+//
+// Parameters:
+//
+//   - rawToken: The raw token
+//
+// Returns:
+//
+//   - string: The hex encoded SHA-256 hash of the raw token
+func hashToken(rawToken string) string {
+	hash := sha256.Sum256([]byte(rawToken))
+	return hex.EncodeToString(hash[:])
+}
+
+// removeExpired removes the expired results, must be called with the mutex held. The results are only kept for the
+// result TTL, so there are few of them
+//
+// Parameters:
+//
+//   - now: The current time
+func (d *refreshDeduplicator) removeExpired(now time.Time) {
+	for key, call := range d.calls {
+		if !call.expiresAt.IsZero() && now.After(call.expiresAt) {
+			delete(d.calls, key)
+		}
+	}
+}
+
+// refresh refreshes the tokens once per refresh token, sharing the result with the concurrent and the immediately
+// following requests that carry the same refresh token
+//
+// Parameters:
+//
+//   - w: The HTTP response writer of the request that performs the refresh
+//   - r: The HTTP request that performs the refresh
+//   - rawRefreshToken: The raw refresh token
+//   - rotatedUntil: The time until which the replay of the refresh token is detected once it's rotated
+//   - refreshTokenFn: The function to refresh the tokens
+//
+// Returns:
+//
+//   - map[gojwttoken.Token]string: The new raw tokens
+//   - error: ErrRefreshTokenReused if the refresh token was already rotated, or the store or refresh error if any
+func (d *refreshDeduplicator) refresh(
+	w http.ResponseWriter,
+	r *http.Request,
+	rawRefreshToken string,
+	rotatedUntil time.Time,
+	refreshTokenFn RefreshTokenFn,
+) (map[gojwttoken.Token]string, error) {
+	key := hashToken(rawRefreshToken)
+	now := time.Now()
+
+	d.mutex.Lock()
+	d.removeExpired(now)
+
+	// Share the in-flight or recently completed refresh
+	if call, ok := d.calls[key]; ok {
+		d.mutex.Unlock()
+		<-call.done
+		return call.rawTokens, call.err
+	}
+
+	// Register the refresh call
+	call := &refreshCall{done: make(chan struct{})}
+	d.calls[key] = call
+	d.mutex.Unlock()
+
+	// Complete the call even if the refresh function panics, so the waiting requests are released
+	call.err = ErrRefreshInterrupted
+	marked := false
+	defer func() {
+		// Remove the rotated mark of a failed refresh, so the next request retries it
+		if call.err != nil && marked {
+			_ = d.rotated.UnmarkRotated(context.WithoutCancel(r.Context()), key)
+		}
+
+		d.mutex.Lock()
+		if call.err != nil {
+			// Don't cache the failures, the next request retries the refresh
+			delete(d.calls, key)
+		} else {
+			call.expiresAt = time.Now().Add(d.resultTTL)
+		}
+		d.mutex.Unlock()
+		close(call.done)
+	}()
+
+	// Mark the refresh token as rotated before refreshing it, so its replay is detected even by other instances
+	// sharing the store
+	rotatedTTL := time.Until(rotatedUntil)
+	if rotatedTTL < d.resultTTL {
+		rotatedTTL = d.resultTTL
+	}
+	marked, call.err = d.rotated.MarkRotated(r.Context(), key, rotatedTTL)
+	if call.err != nil {
+		return nil, call.err
+	}
+	if !marked {
+		call.err = ErrRefreshTokenReused
+		return nil, call.err
+	}
+
+	// Refresh the tokens
+	call.rawTokens, call.err = refreshTokenFn(w, r)
+	return call.rawTokens, call.err
+}
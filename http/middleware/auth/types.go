@@ -1,9 +1,14 @@
 package auth
 
 import (
+	"context"
 	"net/http"
+	"sync"
+	"time"
 
 	gojwttoken "github.com/ralvarezdev/go-jwt/token"
+
+	gonetinternalexpiring "github.com/ralvarezdev/go-net/internal/expiring"
 )
 
 type (
@@ -20,4 +25,85 @@ type (
 		w http.ResponseWriter,
 		r *http.Request,
 	) (map[gojwttoken.Token]string, error)
+
+	// RefreshTokenReusedFn defines the function signature for handling the replay of a rotated refresh token, e.g. to
+	// revoke the whole token family
+	RefreshTokenReusedFn func(
+		r *http.Request,
+		rawRefreshToken string,
+	)
+
+	// MemoryRotatedTokenStore is an in-memory implementation of the RotatedTokenStore interface, it only detects the
+	// replay of the refresh tokens rotated by the same process
+	MemoryRotatedTokenStore struct {
+		rotated *gonetinternalexpiring.Set
+		mutex   sync.Mutex
+	}
 )
+
+// NewMemoryRotatedTokenStore creates a new in-memory rotated token store
+//
+// Returns:
+//
+//   - *MemoryRotatedTokenStore: The in-memory rotated token store
+func NewMemoryRotatedTokenStore() *MemoryRotatedTokenStore {
+	return &MemoryRotatedTokenStore{
+		rotated: gonetinternalexpiring.NewSet(),
+	}
+}
+
+// MarkRotated atomically marks a refresh token hash as rotated for the given TTL
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - tokenHash: The refresh token hash
+//   - ttl: The time the replay of the refresh token is detected
+//
+// Returns:
+//
+//   - bool: True if it was marked, false if it was already marked
+//   - error: The error if any
+func (m *MemoryRotatedTokenStore) MarkRotated(
+	_ context.Context,
+	tokenHash string,
+	ttl time.Duration,
+) (bool, error) {
+	if m == nil {
+		return false, ErrNilRotatedTokenStore
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Check if the token hash is already marked
+	now := time.Now()
+	m.rotated.RemoveExpired(now)
+	if m.rotated.Has(tokenHash) {
+		return false, nil
+	}
+	m.rotated.Add(tokenHash, now.Add(ttl))
+	return true, nil
+}
+
+// UnmarkRotated removes the rotated mark of a refresh token hash, with its expiration time
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - tokenHash: The refresh token hash
+//
+// Returns:
+//
+//   - error: The error if any
+func (m *MemoryRotatedTokenStore) UnmarkRotated(_ context.Context, tokenHash string) error {
+	if m == nil {
+		return ErrNilRotatedTokenStore
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.rotated.Remove(tokenHash)
+	return nil
+}
@@ -0,0 +1,19 @@
+package etag
+
+import (
+	"errors"
+)
+
+var (
+	ErrCodePreconditionFailed   string
+	ErrCodePreconditionRequired string
+	ErrCodeVersionFailed        string
+)
+
+var (
+	ErrNilOptions           = errors.New("etag options cannot be nil")
+	ErrNilVersionFn         = errors.New("version function is required to enforce the preconditions")
+	ErrPreconditionFailed   = errors.New("resource has been modified")
+	ErrPreconditionRequired = errors.New("request must be conditional, set the If-Match or If-Unmodified-Since header")
+	ErrVersionFailed        = errors.New("failed to get the resource version")
+)
@@ -0,0 +1,13 @@
+package etag
+
+import (
+	"net/http"
+)
+
+type (
+	// ETag is the interface for the ETag and conditional requests middleware
+	ETag interface {
+		Handle() func(next http.Handler) http.Handler
+		Require() func(next http.Handler) http.Handler
+	}
+)
@@ -0,0 +1,342 @@
+package etag
+
+import (
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	gonethttp "github.com/ralvarezdev/go-net/http"
+	gonethttphandler "github.com/ralvarezdev/go-net/http/handler"
+)
+
+type (
+	// Middleware struct is the ETag and conditional requests middleware
+	Middleware struct {
+		responsesHandler gonethttphandler.ResponsesHandler
+		options          *Options
+		logger           *slog.Logger
+	}
+
+	// Options is the options for the ETag and conditional requests middleware
+	Options struct {
+		// Weak indicates if the ETags computed from the encoded bodies are weak
+		Weak bool
+
+		// VersionFn returns the current version of the resource of a request. It's used to answer the conditional
+		// GET requests without calling the handler, and to enforce the If-Match and If-Unmodified-Since preconditions
+		VersionFn VersionFn
+
+		// Methods are the HTTP methods whose preconditions are enforced
+		Methods []string
+	}
+)
+
+// NewOptions creates a new Options struct, enforcing the preconditions on the PUT, PATCH and DELETE requests
+//
+// Parameters:
+//
+//   - weak: Whether the ETags computed from the encoded bodies are weak
+//   - versionFn: The function that returns the current version of the resource of a request (can be nil)
+//
+// Returns:
+//
+//   - *Options: The options for the ETag middleware
+func NewOptions(weak bool, versionFn VersionFn) *Options {
+	return &Options{
+		Weak:      weak,
+		VersionFn: versionFn,
+		Methods:   []string{http.MethodPut, http.MethodPatch, http.MethodDelete},
+	}
+}
+
+// NewMiddleware creates a new ETag and conditional requests middleware
+//
+// Parameters:
+//
+//   - responsesHandler: The HTTP handler to handle errors
+//   - options: The options for the middleware
+//   - logger: The logger (can be nil)
+//
+// Returns:
+//
+//   - *Middleware: The ETag middleware
+//   - error: The error if any
+func NewMiddleware(
+	responsesHandler gonethttphandler.ResponsesHandler,
+	options *Options,
+	logger *slog.Logger,
+) (*Middleware, error) {
+	// Check if the responses handler or the options are nil
+	if responsesHandler == nil {
+		return nil, gonethttphandler.ErrNilHandler
+	}
+	if options == nil {
+		return nil, ErrNilOptions
+	}
+	if len(options.Methods) == 0 {
+		options.Methods = []string{http.MethodPut, http.MethodPatch, http.MethodDelete}
+	}
+
+	if logger != nil {
+		logger = logger.With(
+			slog.String("component", "http_middleware_etag"),
+		)
+	}
+
+	return &Middleware{
+		responsesHandler,
+		options,
+		logger,
+	}, nil
+}
+
+// parseTime parses an HTTP date header
+//
+// Parameters:
+//
+//   - value: The header value
+//
+// Returns:
+//
+//   - time.Time: The parsed time
+//   - bool: True if the header is set and valid, false otherwise
+func parseTime(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	parsedTime, err := http.ParseTime(value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return parsedTime, true
+}
+
+// isNotModified checks if the client has the current representation, following the If-None-Match header, or the
+// If-Modified-Since header if the former is not set
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//   - etag: The current ETag header value
+//   - lastModified: The last modification time, can be zero
+//
+// Returns:
+//
+//   - bool: True if the representation was not modified, false otherwise
+func isNotModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if ifNoneMatch := r.Header.Get(gonethttp.IfNoneMatch); ifNoneMatch != "" {
+		return matchETags(ifNoneMatch, etag, false)
+	}
+
+	since, ok := parseTime(r.Header.Get(gonethttp.IfModifiedSince))
+	return ok && !lastModified.IsZero() && !lastModified.Truncate(time.Second).After(since)
+}
+
+// writeNotModified writes a 304 Not Modified response
+//
+// Parameters:
+//
+//   - w: The HTTP response writer
+func writeNotModified(w http.ResponseWriter) {
+	// The representation headers are not sent without a body
+	w.Header().Del(gonethttp.ContentType)
+	w.Header().Del("Content-Length")
+	w.WriteHeader(http.StatusNotModified)
+}
+
+// getVersion gets the current version of the resource of a request, handling the errors
+//
+// Parameters:
+//
+//   - w: The HTTP response writer
+//   - r: The HTTP request
+//
+// Returns:
+//
+//   - *Version: The current version, or nil if the resource does not exist
+//   - bool: True if the version was got, false if an error response was written
+func (m Middleware) getVersion(w http.ResponseWriter, r *http.Request) (*Version, bool) {
+	version, err := m.options.VersionFn(r)
+	if err != nil {
+		if m.logger != nil {
+			m.logger.Error(
+				"Failed to get resource version",
+				slog.Any("error", err),
+			)
+		}
+		m.responsesHandler.HandleDebugErrorWithCode(
+			w,
+			r,
+			err,
+			ErrVersionFailed,
+			ErrCodeVersionFailed,
+			http.StatusInternalServerError,
+		)
+		return nil, false
+	}
+	return version, true
+}
+
+// handleRead answers the conditional GET and HEAD requests. The ETag and Last-Modified headers are set from the
+// current version if the VersionFn returns one, so they match the version checked by the preconditions of the unsafe
+// requests, or computed from the encoded body otherwise
+//
+// Parameters:
+//
+//   - w: The HTTP response writer
+//   - r: The HTTP request
+//   - next: The next handler
+func (m Middleware) handleRead(w http.ResponseWriter, r *http.Request, next http.Handler) {
+	// Answer without calling the handler if the current version is known
+	var version *Version
+	if m.options.VersionFn != nil {
+		var ok bool
+		if version, ok = m.getVersion(w, r); !ok {
+			return
+		}
+		if version != nil && isNotModified(r, version.header(), version.LastModified) {
+			SetVersion(w, version)
+			writeNotModified(w)
+			return
+		}
+	}
+
+	// Buffer the encoded body
+	bufferedWriter := newBufferedResponseWriter(w)
+	next.ServeHTTP(bufferedWriter, r)
+	if bufferedWriter.status != http.StatusOK {
+		bufferedWriter.flush()
+		return
+	}
+
+	// Set the current version, or compute the ETag from the body unless the handler supplied its own version
+	SetVersion(w, version)
+	etag := w.Header().Get(gonethttp.ETag)
+	if etag == "" {
+		etag = ComputeETag(bufferedWriter.body.Bytes(), m.options.Weak)
+		w.Header().Set(gonethttp.ETag, etag)
+	}
+	lastModified, _ := parseTime(w.Header().Get(gonethttp.LastModified))
+	if isNotModified(r, etag, lastModified) {
+		writeNotModified(w)
+		return
+	}
+	bufferedWriter.flush()
+}
+
+// handleWrite enforces the If-Match and If-Unmodified-Since preconditions of the unsafe requests
+//
+// Parameters:
+//
+//   - w: The HTTP response writer
+//   - r: The HTTP request
+//   - next: The next handler
+//   - require: Whether the requests must be conditional
+func (m Middleware) handleWrite(w http.ResponseWriter, r *http.Request, next http.Handler, require bool) {
+	ifMatch := strings.TrimSpace(r.Header.Get(gonethttp.IfMatch))
+	ifUnmodifiedSince, hasIfUnmodifiedSince := parseTime(r.Header.Get(gonethttp.IfUnmodifiedSince))
+	if ifMatch == "" && !hasIfUnmodifiedSince {
+		if !require {
+			next.ServeHTTP(w, r)
+			return
+		}
+		m.responsesHandler.HandleFailFieldErrorWithCode(
+			w,
+			r,
+			gonethttp.IfMatch,
+			ErrPreconditionRequired,
+			ErrCodePreconditionRequired,
+			http.StatusPreconditionRequired,
+		)
+		return
+	}
+
+	// The preconditions can't be checked without the current version
+	if m.options.VersionFn == nil {
+		m.responsesHandler.HandleDebugErrorWithCode(
+			w,
+			r,
+			ErrNilVersionFn,
+			gonethttp.ErrInternalServerError,
+			ErrCodeVersionFailed,
+			http.StatusInternalServerError,
+		)
+		return
+	}
+	version, ok := m.getVersion(w, r)
+	if !ok {
+		return
+	}
+
+	// Check the If-Match header with the strong comparison, or the If-Unmodified-Since header if it's not set
+	field := gonethttp.IfMatch
+	preconditionFailed := false
+	if ifMatch != "" {
+		preconditionFailed = version == nil || (ifMatch != "*" && !matchETags(ifMatch, version.header(), true))
+	} else {
+		field = gonethttp.IfUnmodifiedSince
+		preconditionFailed = version == nil ||
+			(!version.LastModified.IsZero() && version.LastModified.Truncate(time.Second).After(ifUnmodifiedSince))
+	}
+	if preconditionFailed {
+		SetVersion(w, version)
+		m.responsesHandler.HandleFailFieldErrorWithCode(
+			w,
+			r,
+			field,
+			ErrPreconditionFailed,
+			ErrCodePreconditionFailed,
+			http.StatusPreconditionFailed,
+		)
+		return
+	}
+	next.ServeHTTP(w, r)
+}
+
+// handle returns the middleware function
+//
+// Parameters:
+//
+//   - require: Whether the unsafe requests must be conditional
+//
+// Returns:
+//
+//   - func(next http.Handler) http.Handler: The middleware function
+func (m Middleware) handle(require bool) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodGet || r.Method == http.MethodHead:
+					m.handleRead(w, r, next)
+				case slices.Contains(m.options.Methods, r.Method):
+					m.handleWrite(w, r, next, require)
+				default:
+					next.ServeHTTP(w, r)
+				}
+			},
+		)
+	}
+}
+
+// Handle returns the middleware function that sets the ETags, answers the conditional GET requests with 304 and
+// enforces the preconditions of the unsafe requests
+//
+// Returns:
+//
+//   - func(next http.Handler) http.Handler: The middleware function
+func (m Middleware) Handle() func(next http.Handler) http.Handler {
+	return m.handle(false)
+}
+
+// Require returns the middleware function of Handle that also rejects the unsafe requests without preconditions with
+// 428, used on the routes that need optimistic concurrency
+//
+// Returns:
+//
+//   - func(next http.Handler) http.Handler: The middleware function
+func (m Middleware) Require() func(next http.Handler) http.Handler {
+	return m.handle(true)
+}
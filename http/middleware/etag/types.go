@@ -0,0 +1,207 @@
+package etag
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	gonethttp "github.com/ralvarezdev/go-net/http"
+)
+
+type (
+	// Version is the current version of a resource, supplied by the handlers
+	Version struct {
+		// ETag is the opaque entity tag, without quotes
+		ETag string
+
+		// Weak indicates if the entity tag is weak
+		Weak bool
+
+		// LastModified is the last modification time, can be zero
+		LastModified time.Time
+	}
+
+	// VersionFn is the function that returns the current version of the resource of a request, or nil if it does not
+	// exist
+	VersionFn func(r *http.Request) (*Version, error)
+
+	// bufferedResponseWriter is the response writer that buffers the response, so its ETag can be computed before
+	// writing it. It doesn't unwrap the original response writer, so a flush through http.ResponseController can't
+	// write the response around the buffer
+	bufferedResponseWriter struct {
+		http.ResponseWriter
+		status      int
+		body        bytes.Buffer
+		wroteHeader bool
+	}
+)
+
+// FormatETag formats an entity tag as a header value
+//
+// Parameters:
+//
+//   - tag: The opaque entity tag, without quotes
+//   - weak: Whether the entity tag is weak
+//
+// Returns:
+//
+//   - string: The header value, e.g. '"abc"' or 'W/"abc"'
+func FormatETag(tag string, weak bool) string {
+	if weak {
+		return "W/\"" + tag + "\""
+	}
+	return "\"" + tag + "\""
+}
+
+// ComputeETag computes the entity tag of an encoded body
+//
+// Parameters:
+//
+//   - body: The encoded body
+//   - weak: Whether the entity tag is weak
+//
+// Returns:
+//
+//   - string: The header value
+func ComputeETag(body []byte, weak bool) string {
+	hash := sha256.Sum256(body)
+	return FormatETag(hex.EncodeToString(hash[:16]), weak)
+}
+
+// SetVersion sets the ETag and Last-Modified headers of a version, used by the handlers to supply their own version
+// instead of computing it from the body
+//
+// Parameters:
+//
+//   - w: The HTTP response writer
+//   - version: The resource version
+func SetVersion(w http.ResponseWriter, version *Version) {
+	if w == nil || version == nil {
+		return
+	}
+	if version.ETag != "" {
+		w.Header().Set(gonethttp.ETag, FormatETag(version.ETag, version.Weak))
+	}
+	if !version.LastModified.IsZero() {
+		w.Header().Set(gonethttp.LastModified, version.LastModified.UTC().Format(http.TimeFormat))
+	}
+}
+
+// header returns the ETag header value of a version
+//
+// Returns:
+//
+//   - string: The header value, or an empty string if the version has no entity tag
+func (v *Version) header() string {
+	if v == nil || v.ETag == "" {
+		return ""
+	}
+	return FormatETag(v.ETag, v.Weak)
+}
+
+// parseETag parses an entity tag
+//
+// Parameters:
+//
+//   - value: The entity tag, e.g. '"abc"' or 'W/"abc"'
+//
+// Returns:
+//
+//   - string: The opaque tag
+//   - bool: Whether the entity tag is weak
+func parseETag(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	weak := strings.HasPrefix(value, "W/")
+	value = strings.TrimPrefix(value, "W/")
+	return strings.Trim(value, "\""), weak
+}
+
+// matchETags checks if an entity tag matches one of the entity tags of a conditional header
+//
+// Parameters:
+//
+//   - header: The conditional header value, a comma separated list or '*'
+//   - current: The current entity tag header value
+//   - strong: Whether to use the strong comparison, where weak entity tags never match
+//
+// Returns:
+//
+//   - bool: True if the entity tag matches, false otherwise
+func matchETags(header, current string, strong bool) bool {
+	if current == "" {
+		return false
+	}
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+
+	currentTag, currentWeak := parseETag(current)
+	if strong && currentWeak {
+		return false
+	}
+	for value := range strings.SplitSeq(header, ",") {
+		tag, weak := parseETag(value)
+		if strong && weak {
+			continue
+		}
+		if tag == currentTag {
+			return true
+		}
+	}
+	return false
+}
+
+// newBufferedResponseWriter creates a new buffered response writer
+//
+// Parameters:
+//
+//   - w: The HTTP response writer
+//
+// Returns:
+//
+//   - *bufferedResponseWriter: The buffered response writer
+func newBufferedResponseWriter(w http.ResponseWriter) *bufferedResponseWriter {
+	return &bufferedResponseWriter{
+		ResponseWriter: w,
+		status:         http.StatusOK,
+	}
+}
+
+// WriteHeader records the HTTP status, it's written once the handler returns
+//
+// Parameters:
+//
+//   - status: The HTTP status
+func (b *bufferedResponseWriter) WriteHeader(status int) {
+	if b.wroteHeader {
+		return
+	}
+	b.status = status
+	b.wroteHeader = true
+}
+
+// Write buffers the body
+//
+// Parameters:
+//
+//   - body: The body bytes
+//
+// Returns:
+//
+//   - int: The number of bytes buffered
+//   - error: Always nil
+func (b *bufferedResponseWriter) Write(body []byte) (int, error) {
+	if !b.wroteHeader {
+		b.WriteHeader(http.StatusOK)
+	}
+	return b.body.Write(body)
+}
+
+// flush writes the buffered status and body
+func (b *bufferedResponseWriter) flush() {
+	b.ResponseWriter.WriteHeader(b.status)
+	_, _ = b.ResponseWriter.Write(b.body.Bytes())
+}
@@ -0,0 +1,41 @@
+package idempotency
+
+import (
+	"time"
+)
+
+const (
+	// DefaultHeader is the default header that contains the idempotency key
+	DefaultHeader = "Idempotency-Key"
+
+	// ReplayedHeader is the header set on the replayed responses
+	ReplayedHeader = "Idempotent-Replayed"
+
+	// DefaultTTL is the default time the responses are stored to be replayed
+	DefaultTTL = 24 * time.Hour
+
+	// DefaultLockTTL is the default time a key is locked while its first request is in progress
+	DefaultLockTTL = time.Minute
+
+	// DefaultMaxBodySize is the default maximum size of the request bodies read to compute their fingerprint
+	DefaultMaxBodySize = 1 << 20
+
+	// DefaultMaxKeyLength is the default maximum length of the idempotency keys
+	DefaultMaxKeyLength = 255
+)
+
+var (
+	// DefaultReplayedHeaders are the default representation headers stored to be replayed, leaving out the headers
+	// that belong to the first response only, like Set-Cookie, Date or the hop-by-hop headers
+	DefaultReplayedHeaders = []string{
+		"Content-Type",
+		"Content-Language",
+		"Content-Encoding",
+		"Content-Location",
+		"Location",
+		"ETag",
+		"Last-Modified",
+		"Vary",
+		"Link",
+	}
+)
@@ -0,0 +1,28 @@
+package idempotency
+
+import (
+	"errors"
+)
+
+var (
+	ErrCodeMissingIdempotencyKey  string
+	ErrCodeInvalidIdempotencyKey  string
+	ErrCodeIdempotencyKeyReused   string
+	ErrCodeRequestInProgress      string
+	ErrCodeIdempotencyStoreFailed string
+	ErrCodeReadBodyFailed         string
+)
+
+var (
+	ErrNilOptions            = errors.New("idempotency options cannot be nil")
+	ErrNilStore              = errors.New("idempotency store cannot be nil")
+	ErrNilRecord             = errors.New("idempotency record cannot be nil")
+	ErrEmptyHeader           = errors.New("idempotency header name cannot be empty")
+	ErrMissingIdempotencyKey = errors.New("missing idempotency key")
+	ErrInvalidIdempotencyKey = errors.New("idempotency key is too long")
+	ErrIdempotencyKeyReused  = errors.New("idempotency key was already used with a different request")
+	ErrRequestInProgress     = errors.New("a request with the same idempotency key is in progress")
+	ErrIdempotencyStore      = errors.New("failed to access the idempotency store")
+	ErrReadBodyFailed        = errors.New("failed to read request body")
+	ErrLockLost              = errors.New("idempotency key lock is held by another request")
+)
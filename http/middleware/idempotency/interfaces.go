@@ -0,0 +1,33 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+type (
+	// Store is the interface for the idempotency records storage
+	Store interface {
+		// Lock atomically stores the in-progress record, with the request fingerprint and the lock token, for the
+		// given TTL. If a record already exists, it's returned and the lock is not acquired
+		Lock(ctx context.Context, key string, record *Record, ttl time.Duration) (
+			existing *Record,
+			acquired bool,
+			err error,
+		)
+
+		// Save atomically replaces the in-progress record with the completed one for the given TTL, only if the
+		// in-progress record still has the same lock token. Otherwise, it returns ErrLockLost
+		Save(ctx context.Context, key string, record *Record, ttl time.Duration) error
+
+		// Unlock atomically removes the in-progress record, only if it still has the given lock token, so the request
+		// can be retried
+		Unlock(ctx context.Context, key, token string) error
+	}
+
+	// Idempotency is the interface for the idempotency middleware
+	Idempotency interface {
+		Handle() func(next http.Handler) http.Handler
+	}
+)
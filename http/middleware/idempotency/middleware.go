@@ -0,0 +1,432 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	gonethttpctx "github.com/ralvarezdev/go-net/http/context"
+	gonethttphandler "github.com/ralvarezdev/go-net/http/handler"
+	gonethttprequest "github.com/ralvarezdev/go-net/http/request"
+)
+
+type (
+	// Middleware struct is the idempotency middleware, which replays the stored response of the requests retried with
+	// the same idempotency key
+	Middleware struct {
+		responsesHandler gonethttphandler.ResponsesHandler
+		store            Store
+		options          *Options
+		logger           *slog.Logger
+	}
+
+	// Options is the options for the idempotency middleware
+	Options struct {
+		// Header is the header that contains the idempotency key
+		Header string
+
+		// Methods are the HTTP methods the idempotency keys apply to
+		Methods []string
+
+		// Required indicates if the requests with those methods must have an idempotency key
+		Required bool
+
+		// TTL is the time the responses are stored to be replayed
+		TTL time.Duration
+
+		// LockTTL is the time a key is locked while its first request is in progress
+		LockTTL time.Duration
+
+		// MaxKeyLength is the maximum length of the idempotency keys
+		MaxKeyLength int
+
+		// MaxBodySize is the maximum size of the request bodies, read to compute their fingerprint
+		MaxBodySize int64
+
+		// ReplayedHeaders are the response headers stored to be replayed, the other headers are only sent to the
+		// first caller
+		ReplayedHeaders []string
+	}
+)
+
+// NewOptions creates a new Options struct with the default values, applied to the POST and PATCH requests
+//
+// Returns:
+//
+//   - *Options: The options for the idempotency middleware
+func NewOptions() *Options {
+	return &Options{
+		Header:          DefaultHeader,
+		Methods:         []string{http.MethodPost, http.MethodPatch},
+		TTL:             DefaultTTL,
+		LockTTL:         DefaultLockTTL,
+		MaxKeyLength:    DefaultMaxKeyLength,
+		MaxBodySize:     DefaultMaxBodySize,
+		ReplayedHeaders: slices.Clone(DefaultReplayedHeaders),
+	}
+}
+
+// NewMiddleware creates a new idempotency middleware
+//
+// Parameters:
+//
+//   - responsesHandler: The HTTP handler to handle errors
+//   - store: The idempotency records store
+//   - options: The options for the middleware
+//   - logger: The logger (can be nil)
+//
+// Returns:
+//
+//   - *Middleware: The idempotency middleware
+//   - error: The error if any
+func NewMiddleware(
+	responsesHandler gonethttphandler.ResponsesHandler,
+	store Store,
+	options *Options,
+	logger *slog.Logger,
+) (*Middleware, error) {
+	// Check if the responses handler, the store or the options are nil
+	if responsesHandler == nil {
+		return nil, gonethttphandler.ErrNilHandler
+	}
+	if store == nil {
+		return nil, ErrNilStore
+	}
+	if options == nil {
+		return nil, ErrNilOptions
+	}
+
+	// Check the options
+	if options.Header == "" {
+		return nil, ErrEmptyHeader
+	}
+	if len(options.Methods) == 0 {
+		options.Methods = []string{http.MethodPost, http.MethodPatch}
+	}
+	if options.TTL <= 0 {
+		options.TTL = DefaultTTL
+	}
+	if options.LockTTL <= 0 {
+		options.LockTTL = DefaultLockTTL
+	}
+	if options.MaxKeyLength <= 0 {
+		options.MaxKeyLength = DefaultMaxKeyLength
+	}
+	if options.MaxBodySize <= 0 {
+		options.MaxBodySize = DefaultMaxBodySize
+	}
+	if options.ReplayedHeaders == nil {
+		options.ReplayedHeaders = slices.Clone(DefaultReplayedHeaders)
+	}
+
+	if logger != nil {
+		logger = logger.With(
+			slog.String("component", "http_middleware_idempotency"),
+		)
+	}
+
+	return &Middleware{
+		responsesHandler,
+		store,
+		options,
+		logger,
+	}, nil
+}
+
+// storeKey returns the key of the record of an idempotency key, scoped to the authenticated principal
+//
+// Parameters:
+//
+//   - principal: The authenticated principal, empty if the request is anonymous
+//   - key: The idempotency key
+//
+// Returns:
+//
+//   - string: The store key
+func storeKey(principal, key string) string {
+	hash := sha256.Sum256([]byte(principal + "\n" + key))
+	return hex.EncodeToString(hash[:])
+}
+
+// fingerprint returns the fingerprint of a request, built from its method, its URL and its body
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//   - body: The raw request body
+//
+// Returns:
+//
+//   - string: The request fingerprint
+func fingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + "\n" + r.URL.RequestURI() + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// replayedHeader returns the response headers stored to be replayed
+//
+// Parameters:
+//
+//   - header: The response header
+//   - names: The names of the headers to store
+//
+// Returns:
+//
+//   - http.Header: The headers to store
+func replayedHeader(header http.Header, names []string) http.Header {
+	replayed := make(http.Header, len(names))
+	for _, name := range names {
+		if values := header.Values(name); len(values) > 0 {
+			replayed[http.CanonicalHeaderKey(name)] = slices.Clone(values)
+		}
+	}
+	return replayed
+}
+
+// replay writes a stored response, marking it as replayed
+//
+// Parameters:
+//
+//   - w: The HTTP response writer
+//   - record: The completed record
+func replay(w http.ResponseWriter, record *Record) {
+	for name, values := range record.Header {
+		w.Header()[name] = slices.Clone(values)
+	}
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(record.StatusCode)
+	_, _ = w.Write(record.Body)
+}
+
+// storeError handles the errors of the idempotency store
+//
+// Parameters:
+//
+//   - w: The HTTP response writer
+//   - r: The HTTP request
+//   - message: The log message
+//   - err: The error that occurred
+func (m Middleware) storeError(
+	w http.ResponseWriter,
+	r *http.Request,
+	message string,
+	err error,
+) {
+	if m.logger != nil {
+		m.logger.Error(
+			message,
+			slog.Any("error", err),
+		)
+	}
+	m.responsesHandler.HandleDebugErrorWithCode(
+		w,
+		r,
+		err,
+		ErrIdempotencyStore,
+		ErrCodeIdempotencyStoreFailed,
+		http.StatusInternalServerError,
+	)
+}
+
+// unlock removes the in-progress record of a failed request, logging the errors
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - key: The store key
+//   - token: The lock token
+func (m Middleware) unlock(ctx context.Context, key, token string) {
+	if err := m.store.Unlock(ctx, key, token); err != nil && m.logger != nil {
+		m.logger.Error(
+			"Failed to unlock idempotency key",
+			slog.Any("error", err),
+		)
+	}
+}
+
+// Handle returns the middleware function that replays the stored response of the requests retried with the same
+// idempotency key
+//
+// Returns:
+//
+//   - func(next http.Handler) http.Handler: The middleware function
+//
+//nolint:gocognit // The idempotency steps are sequential and kept together for clarity
+func (m Middleware) Handle() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				// Check if the idempotency keys apply to the method
+				if !slices.Contains(m.options.Methods, r.Method) {
+					next.ServeHTTP(w, r)
+					return
+				}
+
+				// Get the idempotency key
+				key := strings.TrimSpace(r.Header.Get(m.options.Header))
+				if key == "" {
+					if !m.options.Required {
+						next.ServeHTTP(w, r)
+						return
+					}
+					m.responsesHandler.HandleFailFieldErrorWithCode(
+						w,
+						r,
+						m.options.Header,
+						ErrMissingIdempotencyKey,
+						ErrCodeMissingIdempotencyKey,
+						http.StatusBadRequest,
+					)
+					return
+				}
+				if len(key) > m.options.MaxKeyLength {
+					m.responsesHandler.HandleFailFieldErrorWithCode(
+						w,
+						r,
+						m.options.Header,
+						ErrInvalidIdempotencyKey,
+						ErrCodeInvalidIdempotencyKey,
+						http.StatusBadRequest,
+					)
+					return
+				}
+
+				// Read the raw body up to its maximum size, and restore it for the downstream decoders
+				var body []byte
+				if r.Body != nil {
+					var err error
+					body, err = io.ReadAll(http.MaxBytesReader(w, r.Body, m.options.MaxBodySize))
+					_ = r.Body.Close()
+					if err != nil {
+						var maxBytesError *http.MaxBytesError
+						if errors.As(err, &maxBytesError) {
+							m.responsesHandler.HandleErrorWithCode(
+								w,
+								r,
+								fmt.Errorf(gonethttprequest.ErrMaxBodySizeExceeded, maxBytesError.Limit),
+								gonethttprequest.ErrCodeMaxBodySizeExceeded,
+								http.StatusRequestEntityTooLarge,
+							)
+							return
+						}
+						m.responsesHandler.HandleDebugErrorWithCode(
+							w,
+							r,
+							err,
+							ErrReadBodyFailed,
+							ErrCodeReadBodyFailed,
+							http.StatusBadRequest,
+						)
+						return
+					}
+				}
+				r.Body = io.NopCloser(bytes.NewReader(body))
+				r.GetBody = func() (io.ReadCloser, error) {
+					return io.NopCloser(bytes.NewReader(body)), nil
+				}
+
+				// Lock the key for the principal
+				recordKey := storeKey(gonethttpctx.GetCtxPrincipal(r), key)
+				requestFingerprint := fingerprint(r, body)
+				token := rand.Text()
+				record, acquired, err := m.store.Lock(
+					r.Context(),
+					recordKey,
+					&Record{
+						Fingerprint: requestFingerprint,
+						Token:       token,
+					},
+					m.options.LockTTL,
+				)
+				if err != nil {
+					m.storeError(w, r, "Failed to lock idempotency key", err)
+					return
+				}
+
+				// Replay the stored response, or reject the request if the key is reused or still in progress
+				if !acquired {
+					switch {
+					case record == nil:
+						m.storeError(w, r, "Failed to get idempotency record", ErrNilRecord)
+					case record.Fingerprint != requestFingerprint:
+						m.responsesHandler.HandleFailFieldErrorWithCode(
+							w,
+							r,
+							m.options.Header,
+							ErrIdempotencyKeyReused,
+							ErrCodeIdempotencyKeyReused,
+							http.StatusUnprocessableEntity,
+						)
+					case !record.Completed:
+						m.responsesHandler.HandleErrorWithCode(
+							w,
+							r,
+							ErrRequestInProgress,
+							ErrCodeRequestInProgress,
+							http.StatusConflict,
+						)
+					default:
+						replay(w, record)
+					}
+					return
+				}
+
+				// Unlock the key if the handler panics, so the request can be retried
+				recorder := newResponseRecorder(w)
+				completed := false
+				defer func() {
+					if !completed {
+						m.unlock(context.WithoutCancel(r.Context()), recordKey, token)
+					}
+				}()
+				next.ServeHTTP(recorder, r)
+				completed = true
+
+				// Unlock the key on server errors, so the request can be retried
+				ctx := context.WithoutCancel(r.Context())
+				if recorder.status >= http.StatusInternalServerError {
+					m.unlock(ctx, recordKey, token)
+					return
+				}
+
+				// Store the response to replay it
+				if err = m.store.Save(
+					ctx,
+					recordKey,
+					&Record{
+						Fingerprint: requestFingerprint,
+						Token:       token,
+						Completed:   true,
+						StatusCode:  recorder.status,
+						Header:      replayedHeader(w.Header(), m.options.ReplayedHeaders),
+						Body:        recorder.body.Bytes(),
+					},
+					m.options.TTL,
+				); err != nil {
+					// If the lock was lost, the record belongs to another request and must be kept
+					if m.logger != nil {
+						m.logger.Error(
+							"Failed to store idempotent response",
+							slog.Any("error", err),
+						)
+					}
+					if !errors.Is(err, ErrLockLost) {
+						m.unlock(ctx, recordKey, token)
+					}
+				}
+			},
+		)
+	}
+}
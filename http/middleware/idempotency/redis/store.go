@@ -0,0 +1,213 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	gonethttpmiddlewareidempotency "github.com/ralvarezdev/go-net/http/middleware/idempotency"
+)
+
+const (
+	// DefaultPrefix is the default prefix of the idempotency keys stored in Redis
+	DefaultPrefix = "idempotency:"
+
+	// lockAttempts is the number of attempts to lock a key whose record expires between the lock and the read
+	lockAttempts = 3
+)
+
+var (
+	ErrNilClient = errors.New("redis client cannot be nil")
+)
+
+var (
+	// saveScript replaces the in-progress record with the completed one, only if it still has the same lock token
+	saveScript = goredis.NewScript(`
+local stored = redis.call('GET', KEYS[1])
+if not stored then
+	return 0
+end
+local record = cjson.decode(stored)
+if record.completed or record.token ~= ARGV[1] then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+return 1
+`)
+
+	// unlockScript removes the in-progress record, only if it still has the same lock token
+	unlockScript = goredis.NewScript(`
+local stored = redis.call('GET', KEYS[1])
+if not stored then
+	return 0
+end
+local record = cjson.decode(stored)
+if record.completed or record.token ~= ARGV[1] then
+	return 0
+end
+return redis.call('DEL', KEYS[1])
+`)
+)
+
+type (
+	// Store is the Redis implementation of the idempotency Store interface
+	Store struct {
+		client goredis.UniversalClient
+		prefix string
+	}
+)
+
+// NewStore creates a new Redis idempotency store
+//
+// Parameters:
+//
+//   - client: The Redis client
+//   - prefix: The prefix of the stored keys, DefaultPrefix if empty
+//
+// Returns:
+//
+//   - *Store: The Redis idempotency store
+//   - error: The error if any
+func NewStore(client goredis.UniversalClient, prefix string) (*Store, error) {
+	if client == nil {
+		return nil, ErrNilClient
+	}
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+
+	return &Store{
+		client: client,
+		prefix: prefix,
+	}, nil
+}
+
+// Lock atomically stores the in-progress record, with the request fingerprint and the lock token, for the given TTL.
+// If a record already exists, it's returned and the lock is not acquired
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - key: The idempotency key
+//   - record: The in-progress record
+//   - ttl: The time to live of the lock
+//
+// Returns:
+//
+//   - *gonethttpmiddlewareidempotency.Record: The existing record, or nil if the lock was acquired
+//   - bool: True if the lock was acquired
+//   - error: The error if any
+func (s *Store) Lock(
+	ctx context.Context,
+	key string,
+	record *gonethttpmiddlewareidempotency.Record,
+	ttl time.Duration,
+) (*gonethttpmiddlewareidempotency.Record, bool, error) {
+	if s == nil {
+		return nil, false, gonethttpmiddlewareidempotency.ErrNilStore
+	}
+	if record == nil {
+		return nil, false, gonethttpmiddlewareidempotency.ErrNilRecord
+	}
+
+	encodedRecord, err := json.Marshal(record)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for range lockAttempts {
+		// Try to create the in-progress record
+		acquired, setErr := s.client.SetNX(ctx, s.prefix+key, encodedRecord, ttl).Result()
+		if setErr != nil {
+			return nil, false, setErr
+		}
+		if acquired {
+			return nil, true, nil
+		}
+
+		// Get the existing record, retrying if it has just expired
+		storedRecord, getErr := s.client.Get(ctx, s.prefix+key).Bytes()
+		if errors.Is(getErr, goredis.Nil) {
+			continue
+		}
+		if getErr != nil {
+			return nil, false, getErr
+		}
+
+		var existing gonethttpmiddlewareidempotency.Record
+		if err = json.Unmarshal(storedRecord, &existing); err != nil {
+			return nil, false, err
+		}
+		return &existing, false, nil
+	}
+	return nil, false, gonethttpmiddlewareidempotency.ErrIdempotencyStore
+}
+
+// Save atomically replaces the in-progress record with the completed one for the given TTL, only if the in-progress
+// record still has the same lock token
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - key: The idempotency key
+//   - record: The completed record
+//   - ttl: The time to live of the record
+//
+// Returns:
+//
+//   - error: The error if any
+func (s *Store) Save(
+	ctx context.Context,
+	key string,
+	record *gonethttpmiddlewareidempotency.Record,
+	ttl time.Duration,
+) error {
+	if s == nil {
+		return gonethttpmiddlewareidempotency.ErrNilStore
+	}
+	if record == nil {
+		return gonethttpmiddlewareidempotency.ErrNilRecord
+	}
+
+	encodedRecord, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	saved, err := saveScript.Run(
+		ctx,
+		s.client,
+		[]string{s.prefix + key},
+		record.Token,
+		encodedRecord,
+		ttl.Milliseconds(),
+	).Int()
+	if err != nil {
+		return err
+	}
+	if saved == 0 {
+		return gonethttpmiddlewareidempotency.ErrLockLost
+	}
+	return nil
+}
+
+// Unlock atomically removes the in-progress record, only if it still has the given lock token, so the request can be
+// retried
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - key: The idempotency key
+//   - token: The lock token
+//
+// Returns:
+//
+//   - error: The error if any
+func (s *Store) Unlock(ctx context.Context, key, token string) error {
+	if s == nil {
+		return gonethttpmiddlewareidempotency.ErrNilStore
+	}
+	return unlockScript.Run(ctx, s.client, []string{s.prefix + key}, token).Err()
+}
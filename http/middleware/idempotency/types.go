@@ -0,0 +1,267 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	gonetinternalexpiring "github.com/ralvarezdev/go-net/internal/expiring"
+)
+
+type (
+	// Record is the stored state of an idempotency key
+	Record struct {
+		// Fingerprint is the fingerprint of the first request sent with the key
+		Fingerprint string `json:"fingerprint"`
+
+		// Token is the token of the lock held by the first request, so only that request can complete or remove the
+		// record
+		Token string `json:"token"`
+
+		// Completed indicates if the first request has finished and its response can be replayed
+		Completed bool `json:"completed"`
+
+		// StatusCode is the HTTP status of the response
+		StatusCode int `json:"status_code,omitempty"`
+
+		// Header is the header of the response
+		Header http.Header `json:"header,omitempty"`
+
+		// Body is the body of the response
+		Body []byte `json:"body,omitempty"`
+	}
+
+	// MemoryStore is an in-memory implementation of the Store interface
+	MemoryStore struct {
+		records     map[string]Record
+		expirations *gonetinternalexpiring.Set
+		mutex       sync.Mutex
+	}
+
+	// responseRecorder is the response writer that records the response while writing it
+	responseRecorder struct {
+		http.ResponseWriter
+		status      int
+		body        bytes.Buffer
+		wroteHeader bool
+	}
+)
+
+// NewMemoryStore creates a new in-memory idempotency store
+//
+// Returns:
+//
+//   - *MemoryStore: The in-memory idempotency store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records:     make(map[string]Record),
+		expirations: gonetinternalexpiring.NewSet(),
+	}
+}
+
+// store stores a record with its expiration time, the mutex must be locked
+//
+// Parameters:
+//
+//   - key: The idempotency key
+//   - record: The record
+//   - expiresAt: The expiration time
+func (m *MemoryStore) store(key string, record *Record, expiresAt time.Time) {
+	m.records[key] = *record
+	m.expirations.Add(key, expiresAt)
+}
+
+// removeExpired removes the expired records, popping only the expired keys from the expiration heap, the mutex must be
+// locked
+//
+// Parameters:
+//
+//   - now: The current time
+func (m *MemoryStore) removeExpired(now time.Time) {
+	for _, key := range m.expirations.RemoveExpired(now) {
+		delete(m.records, key)
+	}
+}
+
+// Lock atomically stores the in-progress record, with the request fingerprint and the lock token, for the given TTL.
+// If a record already exists, it's returned and the lock is not acquired
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - key: The idempotency key
+//   - record: The in-progress record
+//   - ttl: The time to live of the lock
+//
+// Returns:
+//
+//   - *Record: The existing record, or nil if the lock was acquired
+//   - bool: True if the lock was acquired
+//   - error: The error if any
+func (m *MemoryStore) Lock(
+	_ context.Context,
+	key string,
+	record *Record,
+	ttl time.Duration,
+) (*Record, bool, error) {
+	if m == nil {
+		return nil, false, ErrNilStore
+	}
+	if record == nil {
+		return nil, false, ErrNilRecord
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Check if the key already has a record
+	now := time.Now()
+	m.removeExpired(now)
+	if stored, ok := m.records[key]; ok {
+		return &stored, false, nil
+	}
+
+	m.store(key, record, now.Add(ttl))
+	return nil, true, nil
+}
+
+// isLocked checks if the in-progress record of a key still has the given lock token, the mutex must be locked
+//
+// Parameters:
+//
+//   - key: The idempotency key
+//   - token: The lock token
+//
+// Returns:
+//
+//   - bool: True if the in-progress record has the lock token, false otherwise
+func (m *MemoryStore) isLocked(key, token string) bool {
+	stored, ok := m.records[key]
+	if !ok || stored.Completed || stored.Token != token {
+		return false
+	}
+	expiresAt, ok := m.expirations.ExpiresAt(key)
+	return ok && !time.Now().After(expiresAt)
+}
+
+// Save atomically replaces the in-progress record with the completed one for the given TTL, only if the in-progress
+// record still has the same lock token
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - key: The idempotency key
+//   - record: The completed record
+//   - ttl: The time to live of the record
+//
+// Returns:
+//
+//   - error: The error if any
+func (m *MemoryStore) Save(
+	_ context.Context,
+	key string,
+	record *Record,
+	ttl time.Duration,
+) error {
+	if m == nil {
+		return ErrNilStore
+	}
+	if record == nil {
+		return ErrNilRecord
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !m.isLocked(key, record.Token) {
+		return ErrLockLost
+	}
+	m.store(key, record, time.Now().Add(ttl))
+	return nil
+}
+
+// Unlock atomically removes the in-progress record, only if it still has the given lock token, so the request can be
+// retried
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - key: The idempotency key
+//   - token: The lock token
+//
+// Returns:
+//
+//   - error: The error if any
+func (m *MemoryStore) Unlock(_ context.Context, key, token string) error {
+	if m == nil {
+		return ErrNilStore
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.isLocked(key, token) {
+		delete(m.records, key)
+		m.expirations.Remove(key)
+	}
+	return nil
+}
+
+// newResponseRecorder creates a new response recorder
+//
+// Parameters:
+//
+//   - w: The HTTP response writer
+//
+// Returns:
+//
+//   - *responseRecorder: The response recorder
+func newResponseRecorder(w http.ResponseWriter) *responseRecorder {
+	return &responseRecorder{
+		ResponseWriter: w,
+		status:         http.StatusOK,
+	}
+}
+
+// WriteHeader records the HTTP status and writes it
+//
+// Parameters:
+//
+//   - status: The HTTP status
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.wroteHeader {
+		return
+	}
+	r.status = status
+	r.wroteHeader = true
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the body and writes it
+//
+// Parameters:
+//
+//   - body: The body bytes
+//
+// Returns:
+//
+//   - int: The number of bytes written
+//   - error: The error if any
+func (r *responseRecorder) Write(body []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+	r.body.Write(body)
+	return r.ResponseWriter.Write(body)
+}
+
+// Unwrap returns the original response writer, used by http.ResponseController
+//
+// Returns:
+//
+//   - http.ResponseWriter: The original response writer
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,28 @@
+package signature
+
+import (
+	"time"
+)
+
+const (
+	// DefaultSignatureHeader is the default header that contains the request signature
+	DefaultSignatureHeader = "X-Signature"
+
+	// DefaultTimestampHeader is the default header that contains the request timestamp
+	DefaultTimestampHeader = "X-Signature-Timestamp"
+
+	// DefaultNonceHeader is the default header that contains the request nonce
+	DefaultNonceHeader = "X-Signature-Nonce"
+
+	// StripeSignatureHeader is the header used by Stripe-like webhooks
+	StripeSignatureHeader = "Stripe-Signature"
+
+	// GitHubSignatureHeader is the header used by GitHub-like webhooks
+	GitHubSignatureHeader = "X-Hub-Signature-256"
+
+	// DefaultTolerance is the default tolerance window for the request timestamp
+	DefaultTolerance = 5 * time.Minute
+
+	// DefaultMaxBodySize is the default maximum size of the request bodies read to verify their signature
+	DefaultMaxBodySize = 1 << 20
+)
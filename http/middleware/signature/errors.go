@@ -0,0 +1,32 @@
+package signature
+
+import (
+	"errors"
+)
+
+var (
+	ErrCodeMissingSignature   string
+	ErrCodeInvalidSignature   string
+	ErrCodeMissingTimestamp   string
+	ErrCodeInvalidTimestamp   string
+	ErrCodeTimestampOutOfTime string
+	ErrCodeReplayedRequest    string
+	ErrCodeReadBodyFailed     string
+	ErrCodeNonceCacheFailed   string
+)
+
+var (
+	ErrNilOptions           = errors.New("signature options cannot be nil")
+	ErrNilNonceCache        = errors.New("nonce cache cannot be nil")
+	ErrEmptySecrets         = errors.New("at least one signing secret is required")
+	ErrEmptySecret          = errors.New("signing secret cannot be empty")
+	ErrEmptySignatureHeader = errors.New("signature header name cannot be empty")
+	ErrUnknownFormat        = errors.New("unknown signature format")
+	ErrMissingSignature     = errors.New("missing request signature")
+	ErrInvalidSignature     = errors.New("invalid request signature")
+	ErrMissingTimestamp     = errors.New("missing request timestamp")
+	ErrInvalidTimestamp     = errors.New("invalid request timestamp")
+	ErrTimestampOutOfTime   = errors.New("request timestamp is outside the tolerance window")
+	ErrReplayedRequest      = errors.New("request has already been received")
+	ErrReadBodyFailed       = errors.New("failed to read request body")
+)
@@ -0,0 +1,20 @@
+package signature
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+type (
+	// NonceCache is the interface for the replay protection cache
+	NonceCache interface {
+		// CheckAndStore stores the nonce for the given TTL, and reports if it was already stored
+		CheckAndStore(ctx context.Context, nonce string, ttl time.Duration) (seen bool, err error)
+	}
+
+	// Verifier is the interface for the request signature verification middleware
+	Verifier interface {
+		Verify() func(next http.Handler) http.Handler
+	}
+)
@@ -0,0 +1,451 @@
+package signature
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	gonethttp "github.com/ralvarezdev/go-net/http"
+	gonethttphandler "github.com/ralvarezdev/go-net/http/handler"
+	gonethttprequest "github.com/ralvarezdev/go-net/http/request"
+)
+
+type (
+	// Middleware struct is the HMAC request signature verification middleware
+	Middleware struct {
+		responsesHandler gonethttphandler.ResponsesHandler
+		nonceCache       NonceCache
+		options          *Options
+		logger           *slog.Logger
+	}
+
+	// Options is the options for the HMAC request signature verification middleware
+	//
+	// For FormatHex and FormatBase64, the canonical payload is built as:
+	//
+	//	<timestamp>\n<nonce>\n<lowercase header name>:<header value>\n...<raw body>
+	//
+	// where the timestamp and the nonce are empty if their header names are empty, and the headers are the ones listed
+	// in SignedHeaders, in the same order
+	//
+	// The requests with a nonce are rejected if the nonce was already used within twice the tolerance. The requests
+	// without a nonce are only checked if SignatureReplayProtection is enabled, using their signature as the nonce, so
+	// it must be disabled for the senders that redeliver the same signed payload, e.g. GitHub
+	Options struct {
+		Secrets                   []Secret
+		Format                    Format
+		SignatureHeader           string
+		TimestampHeader           string
+		NonceHeader               string
+		SignedHeaders             []string
+		Tolerance                 time.Duration
+		MaxBodySize               int64
+		SignatureReplayProtection bool
+	}
+
+	// signedRequest is the parsed signature information of a request
+	signedRequest struct {
+		timestamp  string
+		signatures [][]byte
+	}
+)
+
+// NewOptions creates a new Options struct with the default headers for the given format
+//
+// Parameters:
+//
+//   - format: The format of the signature header
+//   - secrets: The active signing secrets
+//
+// Returns:
+//
+//   - *Options: The options for the HMAC request signature verification middleware
+func NewOptions(
+	format Format,
+	secrets ...Secret,
+) *Options {
+	options := &Options{
+		Secrets:     secrets,
+		Format:      format,
+		Tolerance:   DefaultTolerance,
+		MaxBodySize: DefaultMaxBodySize,
+	}
+
+	// The signature replay protection is only enabled for the formats that sign a timestamp, since the redeliveries
+	// are signed again with a new one
+	switch format {
+	case FormatStripe:
+		options.SignatureHeader = StripeSignatureHeader
+		options.SignatureReplayProtection = true
+	case FormatGitHub:
+		options.SignatureHeader = GitHubSignatureHeader
+	default:
+		options.SignatureHeader = DefaultSignatureHeader
+		options.TimestampHeader = DefaultTimestampHeader
+		options.NonceHeader = DefaultNonceHeader
+		options.SignatureReplayProtection = true
+	}
+	return options
+}
+
+// NewMiddleware creates a new HMAC request signature verification middleware
+//
+// Parameters:
+//
+//   - responsesHandler: The HTTP handler to handle errors
+//   - nonceCache: The replay protection cache
+//   - options: The options for the middleware
+//   - logger: The logger (can be nil)
+//
+// Returns:
+//
+//   - *Middleware: The HMAC request signature verification middleware
+//   - error: The error if any
+func NewMiddleware(
+	responsesHandler gonethttphandler.ResponsesHandler,
+	nonceCache NonceCache,
+	options *Options,
+	logger *slog.Logger,
+) (*Middleware, error) {
+	// Check if the responses handler, the nonce cache or the options are nil
+	if responsesHandler == nil {
+		return nil, gonethttphandler.ErrNilHandler
+	}
+	if nonceCache == nil {
+		return nil, ErrNilNonceCache
+	}
+	if options == nil {
+		return nil, ErrNilOptions
+	}
+
+	// Check the options
+	if len(options.Secrets) == 0 {
+		return nil, ErrEmptySecrets
+	}
+	for _, secret := range options.Secrets {
+		if len(secret.Key) == 0 {
+			return nil, ErrEmptySecret
+		}
+	}
+	if options.SignatureHeader == "" {
+		return nil, ErrEmptySignatureHeader
+	}
+	if options.Format < FormatHex || options.Format > FormatGitHub {
+		return nil, ErrUnknownFormat
+	}
+	if options.Tolerance <= 0 {
+		options.Tolerance = DefaultTolerance
+	}
+	if options.MaxBodySize <= 0 {
+		options.MaxBodySize = DefaultMaxBodySize
+	}
+
+	if logger != nil {
+		logger = logger.With(
+			slog.String("component", "http_middleware_signature"),
+		)
+	}
+
+	return &Middleware{
+		responsesHandler,
+		nonceCache,
+		options,
+		logger,
+	}, nil
+}
+
+// decodeSignatures decodes the comma-separated signatures with the given decoding function
+//
+// Parameters:
+//
+//   - value: The comma-separated signatures
+//   - decodeFn: The decoding function
+//
+// Returns:
+//
+//   - [][]byte: The decoded signatures
+func decodeSignatures(value string, decodeFn func(string) ([]byte, error)) [][]byte {
+	var signatures [][]byte
+	for _, part := range strings.Split(value, ",") {
+		if decoded, err := decodeFn(strings.TrimSpace(part)); err == nil && len(decoded) > 0 {
+			signatures = append(signatures, decoded)
+		}
+	}
+	return signatures
+}
+
+// parseRequest parses the signature information from the request headers
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//
+// Returns:
+//
+//   - *signedRequest: The parsed signature information
+func (m Middleware) parseRequest(r *http.Request) *signedRequest {
+	value := strings.TrimSpace(r.Header.Get(m.options.SignatureHeader))
+	parsed := &signedRequest{}
+
+	switch m.options.Format {
+	case FormatStripe:
+		for _, part := range strings.Split(value, ",") {
+			key, item, _ := strings.Cut(strings.TrimSpace(part), "=")
+			switch key {
+			case "t":
+				parsed.timestamp = item
+			case "v1":
+				if decoded, err := hex.DecodeString(item); err == nil {
+					parsed.signatures = append(parsed.signatures, decoded)
+				}
+			}
+		}
+	case FormatGitHub:
+		if item, ok := strings.CutPrefix(value, "sha256="); ok {
+			parsed.signatures = decodeSignatures(item, hex.DecodeString)
+		}
+	case FormatBase64:
+		parsed.signatures = decodeSignatures(value, base64.StdEncoding.DecodeString)
+	default:
+		parsed.signatures = decodeSignatures(value, hex.DecodeString)
+	}
+
+	// Get the timestamp from its own header for the formats that do not embed it
+	if m.options.TimestampHeader != "" && parsed.timestamp == "" {
+		parsed.timestamp = strings.TrimSpace(r.Header.Get(m.options.TimestampHeader))
+	}
+	return parsed
+}
+
+// buildPayload builds the signed payload of the request
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//   - timestamp: The request timestamp
+//   - nonce: The request nonce
+//   - body: The raw request body
+//
+// Returns:
+//
+//   - []byte: The signed payload
+func (m Middleware) buildPayload(
+	r *http.Request,
+	timestamp, nonce string,
+	body []byte,
+) []byte {
+	var payload bytes.Buffer
+
+	switch m.options.Format {
+	case FormatStripe:
+		payload.WriteString(timestamp)
+		payload.WriteByte('.')
+	case FormatGitHub:
+	default:
+		payload.WriteString(timestamp)
+		payload.WriteByte('\n')
+		payload.WriteString(nonce)
+		payload.WriteByte('\n')
+		for _, header := range m.options.SignedHeaders {
+			payload.WriteString(strings.ToLower(header))
+			payload.WriteByte(':')
+			payload.WriteString(strings.TrimSpace(r.Header.Get(header)))
+			payload.WriteByte('\n')
+		}
+	}
+	payload.Write(body)
+	return payload.Bytes()
+}
+
+// matchSecret checks the signatures against every active secret
+//
+// Parameters:
+//
+//   - payload: The signed payload
+//   - signatures: The request signatures
+//
+// Returns:
+//
+//   - *Secret: The matched secret, or nil if no signature is valid
+func (m Middleware) matchSecret(payload []byte, signatures [][]byte) *Secret {
+	for i := range m.options.Secrets {
+		mac := hmac.New(sha256.New, m.options.Secrets[i].Key)
+		mac.Write(payload)
+		expected := mac.Sum(nil)
+
+		for _, signature := range signatures {
+			if hmac.Equal(expected, signature) {
+				return &m.options.Secrets[i]
+			}
+		}
+	}
+	return nil
+}
+
+// failHandler is the default fail handler for the signature verification
+//
+// Parameters:
+//
+//   - w: The HTTP response writer
+//   - r: The HTTP request
+//   - err: The error that occurred
+//   - errorCode: The error code to return
+func (m Middleware) failHandler(
+	w http.ResponseWriter,
+	r *http.Request,
+	err error,
+	errorCode string,
+) {
+	m.responsesHandler.HandleErrorWithCode(
+		w,
+		r,
+		err,
+		errorCode,
+		http.StatusUnauthorized,
+	)
+}
+
+// Verify return the middleware function that verifies the request signature
+//
+// Returns:
+//
+//   - func(next http.Handler) http.Handler: The middleware function
+//
+//nolint:gocognit // The verification steps are sequential and kept together for clarity
+func (m Middleware) Verify() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				// Parse the signature information
+				parsed := m.parseRequest(r)
+				if len(parsed.signatures) == 0 {
+					m.failHandler(w, r, ErrMissingSignature, ErrCodeMissingSignature)
+					return
+				}
+
+				// Check the timestamp against the tolerance window
+				if m.options.Format == FormatStripe || m.options.TimestampHeader != "" {
+					if parsed.timestamp == "" {
+						m.failHandler(w, r, ErrMissingTimestamp, ErrCodeMissingTimestamp)
+						return
+					}
+
+					seconds, err := strconv.ParseInt(parsed.timestamp, 10, 64)
+					if err != nil {
+						m.failHandler(w, r, ErrInvalidTimestamp, ErrCodeInvalidTimestamp)
+						return
+					}
+
+					elapsed := time.Since(time.Unix(seconds, 0))
+					if elapsed > m.options.Tolerance || elapsed < -m.options.Tolerance {
+						m.failHandler(w, r, ErrTimestampOutOfTime, ErrCodeTimestampOutOfTime)
+						return
+					}
+				}
+
+				// Read the raw body up to its maximum size, and restore it for the downstream decoders
+				var body []byte
+				if r.Body != nil {
+					var err error
+					body, err = io.ReadAll(http.MaxBytesReader(w, r.Body, m.options.MaxBodySize))
+					_ = r.Body.Close()
+					if err != nil {
+						var maxBytesError *http.MaxBytesError
+						if errors.As(err, &maxBytesError) {
+							m.responsesHandler.HandleErrorWithCode(
+								w,
+								r,
+								fmt.Errorf(gonethttprequest.ErrMaxBodySizeExceeded, maxBytesError.Limit),
+								gonethttprequest.ErrCodeMaxBodySizeExceeded,
+								http.StatusRequestEntityTooLarge,
+							)
+							return
+						}
+						m.responsesHandler.HandleDebugErrorWithCode(
+							w,
+							r,
+							err,
+							ErrReadBodyFailed,
+							ErrCodeReadBodyFailed,
+							http.StatusBadRequest,
+						)
+						return
+					}
+				}
+				r.Body = io.NopCloser(bytes.NewReader(body))
+				r.GetBody = func() (io.ReadCloser, error) {
+					return io.NopCloser(bytes.NewReader(body)), nil
+				}
+
+				// Verify the signature against the active secrets
+				nonce := ""
+				if m.options.NonceHeader != "" {
+					nonce = strings.TrimSpace(r.Header.Get(m.options.NonceHeader))
+				}
+				payload := m.buildPayload(r, parsed.timestamp, nonce, body)
+				secret := m.matchSecret(payload, parsed.signatures)
+				if secret == nil {
+					m.failHandler(w, r, ErrInvalidSignature, ErrCodeInvalidSignature)
+					return
+				}
+
+				// Check the request has not been replayed, falling back to the matched signature if there's no nonce
+				// and the signature replay protection is enabled
+				replayKey := nonce
+				if replayKey == "" && m.options.SignatureReplayProtection {
+					mac := hmac.New(sha256.New, secret.Key)
+					mac.Write(payload)
+					replayKey = hex.EncodeToString(mac.Sum(nil))
+				}
+				if replayKey != "" {
+					seen, err := m.nonceCache.CheckAndStore(
+						r.Context(),
+						replayKey,
+						2*m.options.Tolerance,
+					)
+					if err != nil {
+						if m.logger != nil {
+							m.logger.Error(
+								"Failed to check request nonce",
+								slog.Any("error", err),
+							)
+						}
+						m.responsesHandler.HandleDebugErrorWithCode(
+							w,
+							r,
+							err,
+							gonethttp.ErrInternalServerError,
+							ErrCodeNonceCacheFailed,
+							http.StatusInternalServerError,
+						)
+						return
+					}
+					if seen {
+						m.failHandler(w, r, ErrReplayedRequest, ErrCodeReplayedRequest)
+						return
+					}
+				}
+
+				if m.logger != nil {
+					m.logger.Debug(
+						"Request signature verified",
+						slog.String("secret_id", secret.ID),
+					)
+				}
+
+				// Call the next handler
+				next.ServeHTTP(w, r)
+			},
+		)
+	}
+}
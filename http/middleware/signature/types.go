@@ -0,0 +1,105 @@
+package signature
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	gonetinternalexpiring "github.com/ralvarezdev/go-net/internal/expiring"
+)
+
+type (
+	// Format is the format of the signature header
+	Format int
+
+	// Secret is a signing secret, several secrets can be active at the same time to allow rotation
+	Secret struct {
+		ID  string
+		Key []byte
+	}
+
+	// MemoryNonceCache is an in-memory implementation of the NonceCache interface
+	MemoryNonceCache struct {
+		nonces *gonetinternalexpiring.Set
+		mutex  sync.Mutex
+	}
+)
+
+const (
+	// FormatHex is a hex encoded HMAC-SHA256 of the canonical payload, several comma-separated signatures are allowed
+	FormatHex Format = iota
+
+	// FormatBase64 is a base64 encoded HMAC-SHA256 of the canonical payload, several comma-separated signatures are
+	// allowed
+	FormatBase64
+
+	// FormatStripe is the 't=<timestamp>,v1=<hex>' format, signed over '<timestamp>.<body>'
+	FormatStripe
+
+	// FormatGitHub is the 'sha256=<hex>' format, signed over the raw body
+	FormatGitHub
+)
+
+// NewSecret creates a new signing secret
+//
+// Parameters:
+//
+//   - id: The identifier of the secret, used for logging
+//   - key: The secret key
+//
+// Returns:
+//
+//   - Secret: The signing secret
+func NewSecret(id string, key []byte) Secret {
+	return Secret{
+		ID:  id,
+		Key: key,
+	}
+}
+
+// NewMemoryNonceCache creates a new in-memory nonce cache
+//
+// Returns:
+//
+//   - *MemoryNonceCache: The in-memory nonce cache
+func NewMemoryNonceCache() *MemoryNonceCache {
+	return &MemoryNonceCache{
+		nonces: gonetinternalexpiring.NewSet(),
+	}
+}
+
+// CheckAndStore stores the nonce for the given TTL, and reports if it was already stored
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - nonce: The nonce to store
+//   - ttl: The time to live of the nonce
+//
+// Returns:
+//
+//   - bool: True if the nonce was already stored and has not expired
+//   - error: The error if any
+func (m *MemoryNonceCache) CheckAndStore(
+	_ context.Context,
+	nonce string,
+	ttl time.Duration,
+) (bool, error) {
+	if m == nil {
+		return false, ErrNilNonceCache
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Remove the expired nonces
+	now := time.Now()
+	m.nonces.RemoveExpired(now)
+
+	// Check if the nonce was already stored
+	if m.nonces.Has(nonce) {
+		return true, nil
+	}
+	m.nonces.Add(nonce, now.Add(ttl))
+	return false, nil
+}
@@ -1,8 +1,14 @@
 package http
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"reflect"
+	"strings"
+	"time"
 
 	gonethttproute "github.com/ralvarezdev/go-net/http/route"
 )
@@ -10,13 +16,51 @@ import (
 type (
 	// Module is the struct for the route module
 	Module struct {
+		// Name identifies the module on the modules configuration (see ApplyConfig)
+		Name string
+
+		// Disabled skips the creation of the module and its submodules
+		Disabled bool
+
 		Pattern       string
 		BeforeLoadFn  func(m *Module)
 		AddHandlersFn func(m *Module)
 		AfterLoadFn   func(m *Module)
 		Middlewares   []func(next http.Handler) http.Handler
 		Submodules    []*Module
+
+		// NamedMiddlewares are applied to every request of the module and its submodules, right after Middlewares,
+		// and can be skipped by predicates or excluded per route
+		NamedMiddlewares []*gonethttproute.NamedMiddleware
+
+		// NotFoundHandlerFn and MethodNotAllowedHandlerFn customize the responses to the unmatched requests of the
+		// module and its submodules (can be nil to respond with JSend errors)
+		NotFoundHandlerFn         gonethttproute.NotFoundHandlerFn
+		MethodNotAllowedHandlerFn gonethttproute.MethodNotAllowedHandlerFn
+
+		// Container holds the dependencies shared by the module tree, the submodules get the one of their parent. If
+		// nil, a new container is created
+		Container *Container
+
+		// Provides and Consumes declare the dependencies provided and consumed by the module, keyed by their type
+		// (see Dependency)
+		Provides []reflect.Type
+		Consumes []reflect.Type
+
+		// ProvideFn provides the declared dependencies to the container. It runs before loading the module tree,
+		// after the modules that provide its consumed dependencies
+		ProvideFn func(m *Module) error
+
+		// StartFn and StopFn are the lifecycle hooks of the module, run by Start in dependency order and by Stop in
+		// the reverse order. The router doesn't call them, they're called by Serve, or by the caller that serves the
+		// module handler with its own server
+		StartFn func(ctx context.Context, m *Module) error
+		StopFn  func(ctx context.Context, m *Module) error
 		gonethttproute.RouterWrapper
+
+		path                  string
+		order                 []*Module
+		configuredMiddlewares []*gonethttproute.NamedMiddleware
 	}
 )
 
@@ -71,6 +115,49 @@ func (m *Module) Create(
 		return gonethttproute.ErrNilRouter
 	}
 
+	// Resolve the dependencies of the module tree
+	if err := m.resolveDependencies(baseRouter.FullPath()); err != nil {
+		return err
+	}
+
+	// Log the resolved module tree
+	if logger, mode := baseRouter.Logger(), baseRouter.Mode(); logger != nil && mode != nil && mode.IsDebug() {
+		logger.Debug(
+			"Resolved module tree",
+			slog.String("full_path", baseRouter.FullPath()),
+			slog.String("tree", m.Tree()),
+		)
+	}
+
+	// Check if the module is disabled
+	if m.Disabled {
+		return nil
+	}
+	return m.create(baseRouter)
+}
+
+// namedMiddlewares returns the named middlewares of the module, followed by the ones added by the modules
+// configuration
+//
+// Returns:
+//
+//   - []*gonethttproute.NamedMiddleware: The named middlewares
+func (m *Module) namedMiddlewares() []*gonethttproute.NamedMiddleware {
+	namedMiddlewares := make([]*gonethttproute.NamedMiddleware, 0, len(m.NamedMiddlewares)+len(m.configuredMiddlewares))
+	namedMiddlewares = append(namedMiddlewares, m.NamedMiddlewares...)
+	return append(namedMiddlewares, m.configuredMiddlewares...)
+}
+
+// create creates the router for the module and its submodules, and loads the module
+//
+// Parameters:
+//
+//   - baseRouter: The base router to create the module's router group
+//
+// Returns:
+//
+//   - error: The error if any
+func (m *Module) create(baseRouter gonethttproute.RouterWrapper) error {
 	// Run the before load function
 	if m.BeforeLoadFn != nil {
 		m.BeforeLoadFn(m)
@@ -87,6 +174,21 @@ func (m *Module) Create(
 		return err
 	}
 
+	// Add the named middlewares, followed by the configured ones
+	if namedMiddlewares := m.namedMiddlewares(); len(namedMiddlewares) > 0 {
+		if err = m.Use(namedMiddlewares...); err != nil {
+			return err
+		}
+	}
+
+	// Set the unmatched requests handlers, before creating the submodules so they inherit them
+	if m.NotFoundHandlerFn != nil {
+		m.SetNotFoundHandler(m.NotFoundHandlerFn)
+	}
+	if m.MethodNotAllowedHandlerFn != nil {
+		m.SetMethodNotAllowedHandler(m.MethodNotAllowedHandlerFn)
+	}
+
 	// Create the submodules router
 	router := m.GetRouter()
 	if m.Submodules != nil {
@@ -94,8 +196,11 @@ func (m *Module) Create(
 			if submodule == nil {
 				return fmt.Errorf(ErrNilSubmodule, m.Pattern, i)
 			}
+			if submodule.Disabled {
+				continue
+			}
 
-			if createErr := submodule.Create(router); createErr != nil {
+			if createErr := submodule.create(router); createErr != nil {
 				return createErr
 			}
 		}
@@ -124,3 +229,311 @@ func (m *Module) GetRouter() gonethttproute.RouterWrapper {
 	}
 	return m.RouterWrapper
 }
+
+// modulePath returns the path of a module, used to identify it on the errors
+//
+// Parameters:
+//
+//   - basePath: The path of the parent module
+//   - pattern: The pattern of the module
+//
+// Returns:
+//
+//   - string: The module path
+func modulePath(basePath, pattern string) string {
+	_, path, err := gonethttproute.SplitPattern(pattern)
+	if err != nil {
+		path = pattern
+	}
+	return gonethttproute.JoinPaths(basePath, path)
+}
+
+// collect collects the enabled modules of the tree in pre-order, sharing the container and setting their paths
+//
+// Parameters:
+//
+//   - basePath: The path of the parent module
+//   - container: The container of the module tree
+//   - modules: The collected modules
+//
+// Returns:
+//
+//   - error: The error if any
+func (m *Module) collect(basePath string, container *Container, modules *[]*Module) error {
+	m.path = modulePath(basePath, m.Pattern)
+	m.Container = container
+	if m.Disabled {
+		return nil
+	}
+	*modules = append(*modules, m)
+
+	for i, submodule := range m.Submodules {
+		if submodule == nil {
+			return fmt.Errorf(ErrNilSubmodule, m.path, i)
+		}
+		if err := submodule.collect(m.path, container, modules); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveDependencies checks the dependencies declared by the module tree, sorts the modules in dependency order and
+// runs their provide functions
+//
+// Parameters:
+//
+//   - basePath: The path of the base router
+//
+// Returns:
+//
+//   - error: The error if any
+func (m *Module) resolveDependencies(basePath string) error {
+	// Collect the modules of the tree
+	if m.Container == nil {
+		m.Container = NewContainer()
+	}
+	var modules []*Module
+	if err := m.collect(basePath, m.Container, &modules); err != nil {
+		return err
+	}
+
+	// Get the providers of the dependencies
+	providers := make(map[reflect.Type]*Module)
+	for _, module := range modules {
+		for i, dependency := range module.Provides {
+			if dependency == nil {
+				return fmt.Errorf(ErrNilDependency, module.path, i)
+			}
+			if provider, ok := providers[dependency]; ok {
+				return fmt.Errorf(ErrDuplicateProvider, module.path, dependency, provider.path)
+			}
+			providers[dependency] = module
+		}
+	}
+
+	// Get the modules each module depends on, the dependencies already in the container are provided externally
+	dependencies := make(map[*Module][]*Module)
+	for _, module := range modules {
+		for i, dependency := range module.Consumes {
+			if dependency == nil {
+				return fmt.Errorf(ErrNilDependency, module.path, i)
+			}
+
+			provider, ok := providers[dependency]
+			switch {
+			case ok && provider != module:
+				dependencies[module] = append(dependencies[module], provider)
+			case !ok && !m.Container.Has(dependency):
+				return fmt.Errorf(ErrMissingDependency, module.path, dependency)
+			}
+		}
+	}
+
+	// Sort the modules in dependency order, keeping the tree order between the independent ones
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	states := make(map[*Module]int)
+	order := make([]*Module, 0, len(modules))
+	var stack []*Module
+	var visit func(module *Module) error
+	visit = func(module *Module) error {
+		switch states[module] {
+		case visited:
+			return nil
+		case visiting:
+			// Build the cycle from the stack
+			var cycle []string
+			for i := len(stack) - 1; i >= 0; i-- {
+				cycle = append([]string{stack[i].path}, cycle...)
+				if stack[i] == module {
+					break
+				}
+			}
+			cycle = append(cycle, module.path)
+			return fmt.Errorf(ErrCyclicDependency, module.path, strings.Join(cycle, " -> "))
+		}
+
+		states[module] = visiting
+		stack = append(stack, module)
+		for _, dependency := range dependencies[module] {
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		states[module] = visited
+		order = append(order, module)
+		return nil
+	}
+	for _, module := range modules {
+		if err := visit(module); err != nil {
+			return err
+		}
+	}
+	m.order = order
+
+	// Run the provide functions
+	for _, module := range order {
+		if module.ProvideFn != nil {
+			if err := module.ProvideFn(module); err != nil {
+				return fmt.Errorf(ErrProvideFailed, module.path, err)
+			}
+		}
+		for _, dependency := range module.Provides {
+			if !m.Container.Has(dependency) {
+				return fmt.Errorf(ErrUnprovidedDependency, module.path, dependency)
+			}
+		}
+	}
+	return nil
+}
+
+// Path returns the full path of the module, set by Create
+//
+// Returns:
+//
+//   - string: The module path
+func (m *Module) Path() string {
+	if m == nil {
+		return ""
+	}
+	return m.path
+}
+
+// Start runs the start functions of the module tree in dependency order. If one fails, the already started modules
+// are stopped in the reverse order. It's called by Serve, or it must be called before serving the module handler with
+// another server
+//
+// Parameters:
+//
+//   - ctx: The context
+//
+// Returns:
+//
+//   - error: The error if any
+func (m *Module) Start(ctx context.Context) error {
+	if m == nil {
+		return ErrNilModule
+	}
+	if m.order == nil {
+		return ErrModuleNotCreated
+	}
+
+	for i, module := range m.order {
+		if module.StartFn == nil {
+			continue
+		}
+		if err := module.StartFn(ctx, module); err != nil {
+			return errors.Join(
+				fmt.Errorf(ErrStartFailed, module.path, err),
+				stopModules(ctx, m.order[:i]),
+			)
+		}
+	}
+	return nil
+}
+
+// Stop runs the stop functions of the module tree in the reverse dependency order. All the modules are stopped even
+// if some fail. It's called by Serve, or it must be called after shutting down another server that serves the module
+// handler
+//
+// Parameters:
+//
+//   - ctx: The context
+//
+// Returns:
+//
+//   - error: The joined errors if any
+func (m *Module) Stop(ctx context.Context) error {
+	if m == nil {
+		return ErrNilModule
+	}
+	if m.order == nil {
+		return ErrModuleNotCreated
+	}
+	return stopModules(ctx, m.order)
+}
+
+// Serve starts the module tree, serves it with the server until the context is done, and stops the module tree once
+// the server is shut down. The server handler must be the one of the base router the module was created with, e.g.
+// its Handler or Compile result. It serves plain HTTP, so the TLS servers must call Start and Stop around their own
+// ListenAndServeTLS
+//
+// Parameters:
+//
+//   - ctx: The context, the server is shut down once it's done
+//   - server: The HTTP server
+//   - shutdownTimeout: The time the server waits for the in-flight requests, DefaultShutdownTimeout if zero
+//
+// Returns:
+//
+//   - error: The joined errors if any
+func (m *Module) Serve(ctx context.Context, server *http.Server, shutdownTimeout time.Duration) error {
+	if m == nil {
+		return ErrNilModule
+	}
+	if server == nil {
+		return ErrNilServer
+	}
+	if server.Handler == nil {
+		return ErrNilServerHandler
+	}
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = DefaultShutdownTimeout
+	}
+
+	// Start the module tree
+	if err := m.Start(ctx); err != nil {
+		return err
+	}
+
+	// Serve until the server fails or the context is done
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+
+	var err error
+	select {
+	case err = <-serveErr:
+	case <-ctx.Done():
+		// Shut down the server, waiting for the in-flight requests
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+		defer cancel()
+		err = server.Shutdown(shutdownCtx)
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		err = nil
+	}
+
+	// Stop the module tree, even if the context is done
+	return errors.Join(err, m.Stop(context.WithoutCancel(ctx)))
+}
+
+// stopModules runs the stop functions of the modules in the reverse order
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - modules: The modules in dependency order
+//
+// Returns:
+//
+//   - error: The joined errors if any
+func stopModules(ctx context.Context, modules []*Module) error {
+	var errs []error
+	for i := len(modules) - 1; i >= 0; i-- {
+		module := modules[i]
+		if module.StopFn == nil {
+			continue
+		}
+		if err := module.StopFn(ctx, module); err != nil {
+			errs = append(errs, fmt.Errorf(ErrStopFailed, module.path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,345 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	gonethttproute "github.com/ralvarezdev/go-net/http/route"
+)
+
+type (
+	// ModuleConfig is the declarative configuration of a module
+	ModuleConfig struct {
+		// Enabled enables or disables the module and its submodules, if nil the module keeps its state
+		Enabled *bool `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+
+		// Pattern overrides the pattern of the module, if empty the module keeps its pattern
+		Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+
+		// Middlewares are the names of the registered named middlewares added to the module
+		Middlewares []string `json:"middlewares,omitempty" yaml:"middlewares,omitempty"`
+	}
+
+	// ModulesConfig is the declarative configuration of a module tree, keyed by the module names
+	ModulesConfig struct {
+		Modules map[string]*ModuleConfig `json:"modules" yaml:"modules"`
+	}
+
+	// MiddlewareRegistry is the registry of the named middlewares that can be added to the modules by their names
+	MiddlewareRegistry struct {
+		middlewares map[string]*gonethttproute.NamedMiddleware
+		mutex       sync.RWMutex
+	}
+)
+
+const (
+	// EnvModuleEnabledSuffix is the suffix of the environment variables that enable or disable a module
+	EnvModuleEnabledSuffix = "_ENABLED"
+
+	// EnvModulePatternSuffix is the suffix of the environment variables that override the pattern of a module
+	EnvModulePatternSuffix = "_PATTERN"
+
+	// EnvModuleMiddlewaresSuffix is the suffix of the environment variables that add middlewares to a module, as a
+	// comma separated list of names
+	EnvModuleMiddlewaresSuffix = "_MIDDLEWARES"
+)
+
+// NewMiddlewareRegistry creates a new middleware registry
+//
+// Returns:
+//
+//   - *MiddlewareRegistry: The middleware registry
+func NewMiddlewareRegistry() *MiddlewareRegistry {
+	return &MiddlewareRegistry{
+		middlewares: make(map[string]*gonethttproute.NamedMiddleware),
+	}
+}
+
+// Register registers a named middleware by its name, so its skip predicates, groups and ordering hints are kept when
+// it's added to a module
+//
+// Parameters:
+//
+//   - middleware: The named middleware
+//
+// Returns:
+//
+//   - error: The error if any
+func (r *MiddlewareRegistry) Register(middleware *gonethttproute.NamedMiddleware) error {
+	if r == nil {
+		return ErrNilMiddlewareRegistry
+	}
+	if middleware == nil {
+		return ErrNilNamedMiddleware
+	}
+	if middleware.Name == "" {
+		return ErrEmptyMiddlewareName
+	}
+	if middleware.Middleware == nil {
+		return fmt.Errorf(ErrNilRegisteredMiddleware, middleware.Name)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.middlewares[middleware.Name]; ok {
+		return fmt.Errorf(ErrDuplicateMiddleware, middleware.Name)
+	}
+	r.middlewares[middleware.Name] = middleware
+	return nil
+}
+
+// Get gets a registered named middleware by its name
+//
+// Parameters:
+//
+//   - name: The middleware name
+//
+// Returns:
+//
+//   - *gonethttproute.NamedMiddleware: The named middleware
+//   - bool: True if the middleware is registered, false otherwise
+func (r *MiddlewareRegistry) Get(name string) (*gonethttproute.NamedMiddleware, bool) {
+	if r == nil {
+		return nil, false
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	middleware, ok := r.middlewares[name]
+	return middleware, ok
+}
+
+// LoadModulesConfig loads the modules configuration from its encoded data
+//
+// Parameters:
+//
+//   - data: The encoded configuration
+//   - unmarshal: The function to decode the configuration, e.g. yaml.Unmarshal. If nil, it's decoded as JSON
+//
+// Returns:
+//
+//   - *ModulesConfig: The modules configuration
+//   - error: The error if any
+func LoadModulesConfig(data []byte, unmarshal func(data []byte, v any) error) (*ModulesConfig, error) {
+	if unmarshal == nil {
+		unmarshal = json.Unmarshal
+	}
+
+	var config ModulesConfig
+	if err := unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf(ErrInvalidModulesConfig, err)
+	}
+	return &config, nil
+}
+
+// LoadModulesConfigFromEnv loads the modules configuration from the environment variables, named as the prefix
+// followed by the module name in uppercase and the field suffix, e.g. 'MODULES_USERS_ENABLED=false',
+// 'MODULES_USERS_PATTERN=/v2/users' or 'MODULES_USERS_MIDDLEWARES=auth,audit'
+//
+// Parameters:
+//
+//   - prefix: The prefix of the environment variables
+//
+// Returns:
+//
+//   - *ModulesConfig: The modules configuration
+//   - error: The error if any
+func LoadModulesConfigFromEnv(prefix string) (*ModulesConfig, error) {
+	config := &ModulesConfig{Modules: make(map[string]*ModuleConfig)}
+	getModuleConfig := func(name string) *ModuleConfig {
+		name = normalizeModuleName(name)
+		moduleConfig, ok := config.Modules[name]
+		if !ok {
+			moduleConfig = &ModuleConfig{}
+			config.Modules[name] = moduleConfig
+		}
+		return moduleConfig
+	}
+
+	for _, env := range os.Environ() {
+		key, value, _ := strings.Cut(env, "=")
+		name, ok := strings.CutPrefix(key, prefix)
+		if !ok {
+			continue
+		}
+
+		if moduleName, found := strings.CutSuffix(name, EnvModuleEnabledSuffix); found && moduleName != "" {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf(ErrInvalidModuleEnv, key, err)
+			}
+			getModuleConfig(moduleName).Enabled = &enabled
+		} else if moduleName, found = strings.CutSuffix(name, EnvModulePatternSuffix); found && moduleName != "" {
+			getModuleConfig(moduleName).Pattern = value
+		} else if moduleName, found = strings.CutSuffix(
+			name,
+			EnvModuleMiddlewaresSuffix,
+		); found && moduleName != "" {
+			moduleConfig := getModuleConfig(moduleName)
+			for middlewareName := range strings.SplitSeq(value, ",") {
+				if middlewareName = strings.TrimSpace(middlewareName); middlewareName != "" {
+					moduleConfig.Middlewares = append(moduleConfig.Middlewares, middlewareName)
+				}
+			}
+		}
+	}
+	return config, nil
+}
+
+// normalizeModuleName normalizes a module name to match the configuration keys, ignoring the case and treating the
+// '-', '.' and ' ' characters as '_'
+//
+// Parameters:
+//
+//   - name: The module name
+//
+// Returns:
+//
+//   - string: The normalized module name
+func normalizeModuleName(name string) string {
+	return strings.NewReplacer("-", "_", ".", "_", " ", "_").Replace(strings.ToLower(name))
+}
+
+// ApplyConfig applies the modules configuration to the module tree, before creating it. The modules are matched by
+// their names, and the configured middlewares are added after the NamedMiddlewares of each module, replacing the ones
+// of a previous call
+//
+// Parameters:
+//
+//   - config: The modules configuration
+//   - registry: The registry of the named middlewares added by name, can be nil if no middlewares are added
+//
+// Returns:
+//
+//   - error: The error if any
+func (m *Module) ApplyConfig(config *ModulesConfig, registry *MiddlewareRegistry) error {
+	if m == nil {
+		return ErrNilModule
+	}
+	if config == nil {
+		return ErrNilModulesConfig
+	}
+
+	// Collect the named modules of the tree
+	modules := make(map[string]*Module)
+	var collect func(module *Module) error
+	collect = func(module *Module) error {
+		if module.Name != "" {
+			name := normalizeModuleName(module.Name)
+			if _, ok := modules[name]; ok {
+				return fmt.Errorf(ErrDuplicateModuleName, module.Name)
+			}
+			modules[name] = module
+		}
+		for _, submodule := range module.Submodules {
+			if submodule != nil {
+				if err := collect(submodule); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err := collect(m); err != nil {
+		return err
+	}
+
+	// Check the unknown modules and middlewares before applying the configuration
+	var unknownModules []string
+	for name, moduleConfig := range config.Modules {
+		if _, ok := modules[normalizeModuleName(name)]; !ok {
+			unknownModules = append(unknownModules, name)
+			continue
+		}
+		if moduleConfig == nil {
+			continue
+		}
+		for _, middlewareName := range moduleConfig.Middlewares {
+			if _, ok := registry.Get(middlewareName); !ok {
+				return fmt.Errorf(ErrUnknownMiddleware, name, middlewareName)
+			}
+		}
+	}
+	if len(unknownModules) > 0 {
+		slices.Sort(unknownModules)
+		return fmt.Errorf(ErrUnknownModules, strings.Join(unknownModules, ", "))
+	}
+
+	// Apply the configuration
+	for name, moduleConfig := range config.Modules {
+		if moduleConfig == nil {
+			continue
+		}
+
+		module := modules[normalizeModuleName(name)]
+		if moduleConfig.Enabled != nil {
+			module.Disabled = !*moduleConfig.Enabled
+		}
+		if moduleConfig.Pattern != "" {
+			module.Pattern = moduleConfig.Pattern
+		}
+		configuredMiddlewares := make([]*gonethttproute.NamedMiddleware, 0, len(moduleConfig.Middlewares))
+		for _, middlewareName := range moduleConfig.Middlewares {
+			middleware, _ := registry.Get(middlewareName)
+			configuredMiddlewares = append(configuredMiddlewares, middleware)
+		}
+		module.configuredMiddlewares = configuredMiddlewares
+	}
+	return nil
+}
+
+// Tree returns the resolved module tree, one module per line, with its path, state and named middlewares
+//
+// Returns:
+//
+//   - string: The module tree
+func (m *Module) Tree() string {
+	if m == nil {
+		return ""
+	}
+
+	var builder strings.Builder
+	var write func(module *Module, depth int)
+	write = func(module *Module, depth int) {
+		builder.WriteString(strings.Repeat("  ", depth))
+		builder.WriteString("- ")
+		if module.Name != "" {
+			builder.WriteString(module.Name)
+			builder.WriteString(" ")
+		}
+
+		path := module.path
+		if path == "" {
+			path = module.Pattern
+		}
+		builder.WriteString(path)
+		if module.Disabled {
+			builder.WriteString(" (disabled)")
+		}
+		if middlewares := module.namedMiddlewares(); len(middlewares) > 0 {
+			names := make([]string, len(middlewares))
+			for i, middleware := range middlewares {
+				names[i] = middleware.Name
+			}
+			builder.WriteString(" [")
+			builder.WriteString(strings.Join(names, ", "))
+			builder.WriteString("]")
+		}
+		builder.WriteString("\n")
+
+		for _, submodule := range module.Submodules {
+			if submodule != nil {
+				write(submodule, depth+1)
+			}
+		}
+	}
+	write(m, 0)
+	return builder.String()
+}
@@ -31,8 +31,20 @@ func BodyDecodeErrorHandler(
 		syntaxError        *json.SyntaxError
 		maxBytesError      *http.MaxBytesError
 		unmarshalTypeError *json.UnmarshalTypeError
+		duplicateKeyError  *DuplicateKeyError
+		maxDepthError      *MaxDepthError
+		fieldsError        *FieldsError
 	)
 
+	// Check if the error contains all the invalid fields
+	if errors.As(err, &fieldsError) {
+		return gonethttpresponse.NewFailDataErrorWithCode(
+			fieldsError.Data,
+			ErrCodeInvalidFields,
+			http.StatusBadRequest,
+		)
+	}
+
 	// Check if the error is an UnmarshalTypeError
 	if errors.As(err, &unmarshalTypeError) {
 		// Check which field failed
@@ -84,6 +96,26 @@ func BodyDecodeErrorHandler(
 		)
 	}
 
+	// Check if the error is a duplicate key error
+	if errors.As(err, &duplicateKeyError) {
+		return gonethttpresponse.NewFailFieldErrorWithCode(
+			duplicateKeyError.Key,
+			duplicateKeyError,
+			ErrCodeDuplicateKey,
+			http.StatusBadRequest,
+		)
+	}
+
+	// Check if the error is a max depth error
+	if errors.As(err, &maxDepthError) {
+		return gonethttpresponse.NewFailFieldErrorWithCode(
+			maxDepthError.Path,
+			maxDepthError,
+			ErrCodeMaxDepthExceeded,
+			http.StatusBadRequest,
+		)
+	}
+
 	// Check if the error is caused by an empty request body
 	if errors.Is(err, io.EOF) {
 		return gonethttpresponse.NewErrorWithCode(
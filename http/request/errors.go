@@ -15,6 +15,9 @@ var (
 	ErrCodeEmptyBody                  string
 	ErrCodeMaxBodySizeExceeded        string
 	ErrCodeNilDecoder                 string
+	ErrCodeDuplicateKey               string
+	ErrCodeMaxDepthExceeded           string
+	ErrCodeInvalidFields              string
 )
 
 const (
@@ -22,6 +25,10 @@ const (
 	ErrMaxBodySizeExceeded     = "json body size exceeds the maximum allowed size, limit is %d bytes"
 	ErrSyntaxError             = "json body contains badly-formed JSON at position %d"
 	ErrUnknownField            = "json body contains an unknown field %s"
+	ErrDuplicateKey            = "json body contains the duplicate key %s"
+	ErrMaxDepthExceeded        = "json body exceeds the maximum nesting depth, limit is %d"
+	ErrInvalidFields           = "json body contains invalid fields: %v"
+	ErrRequiredField           = "%s is required"
 )
 
 var (
@@ -0,0 +1,319 @@
+package json
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	gonethttprequest "github.com/ralvarezdev/go-net/http/request"
+	gonethttpresponse "github.com/ralvarezdev/go-net/http/response"
+)
+
+type (
+	// structField is a decodable field of a struct
+	structField struct {
+		name     string
+		typ      reflect.Type
+		required bool
+		quoted   bool
+	}
+
+	// collector collects all the field errors of a JSON body decoded into a destination type
+	collector struct {
+		options *Options
+		errs    gonethttprequest.FieldsError
+	}
+)
+
+var (
+	// structFieldsCache caches the decodable fields of the struct types
+	structFieldsCache sync.Map
+
+	// jsonUnmarshalerType is the type of the json.Unmarshaler interface
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+
+	// textUnmarshalerType is the type of the encoding.TextUnmarshaler interface
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// getStructFields returns the decodable fields of a struct type, following the encoding/json rules. The fields whose
+// JSON tag doesn't contain 'omitempty' are required, following the same rule as the validator, so the 'omitzero'
+// option alone doesn't make a field optional
+//
+// Parameters:
+//
+//   - typ: The struct type
+//
+// Returns:
+//
+//   - []structField: The struct fields
+func getStructFields(typ reflect.Type) []structField {
+	if fields, ok := structFieldsCache.Load(typ); ok {
+		return fields.([]structField)
+	}
+
+	var fields []structField
+	names := make(map[string]struct{})
+	var add func(typ reflect.Type)
+	add = func(typ reflect.Type) {
+		var embedded []reflect.Type
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			tag := field.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, tagOptions, _ := strings.Cut(tag, ",")
+
+			// Promote the fields of the embedded structs without a name after the ones of this level
+			fieldType := field.Type
+			if field.Anonymous && name == "" {
+				if fieldType.Kind() == reflect.Pointer {
+					fieldType = fieldType.Elem()
+				}
+				if fieldType.Kind() == reflect.Struct {
+					embedded = append(embedded, fieldType)
+					continue
+				}
+			}
+			if !field.IsExported() {
+				continue
+			}
+
+			if name == "" {
+				name = field.Name
+			}
+			if _, ok := names[name]; ok {
+				continue
+			}
+			names[name] = struct{}{}
+
+			isQuoted := false
+			for option := range strings.SplitSeq(tagOptions, ",") {
+				if option == "string" {
+					isQuoted = true
+				}
+			}
+			fields = append(
+				fields, structField{
+					name:     name,
+					typ:      field.Type,
+					required: !strings.Contains(tag, "omitempty"),
+					quoted:   isQuoted,
+				},
+			)
+		}
+		for _, embeddedType := range embedded {
+			add(embeddedType)
+		}
+	}
+	add(typ)
+
+	structFieldsCache.Store(typ, fields)
+	return fields
+}
+
+// typeError returns the error of a value that doesn't match its destination type
+//
+// Parameters:
+//
+//   - typ: The destination type
+//
+// Returns:
+//
+//   - error: The type error
+func typeError(typ reflect.Type) error {
+	typeName := typ.Name()
+	if typeName == "" {
+		typeName = typ.String()
+	}
+	return fmt.Errorf(gonethttpresponse.ErrInvalidFieldValueType, typeName)
+}
+
+// checkLeaf checks if a value can be decoded into its destination type by decoding it
+//
+// Parameters:
+//
+//   - value: The decoded JSON value
+//   - typ: The destination type
+//   - path: The path segments of the value
+func (c *collector) checkLeaf(value any, typ reflect.Type, path []string) {
+	encodedValue, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	if err = json.Unmarshal(encodedValue, reflect.New(typ).Interface()); err != nil {
+		c.errs.Add(path, typeError(typ))
+	}
+}
+
+// check checks a decoded JSON value against its destination type, collecting the errors
+//
+// Parameters:
+//
+//   - value: The decoded JSON value
+//   - typ: The destination type
+//   - path: The path segments of the value
+func (c *collector) check(value any, typ reflect.Type, path []string) {
+	// The null values are always decodable
+	if value == nil {
+		return
+	}
+
+	// The types with custom decoding are checked by decoding them
+	pointerType := reflect.PointerTo(typ)
+	if typ.Kind() != reflect.Pointer &&
+		(pointerType.Implements(jsonUnmarshalerType) || pointerType.Implements(textUnmarshalerType)) {
+		c.checkLeaf(value, typ, path)
+		return
+	}
+
+	switch typ.Kind() {
+	case reflect.Pointer:
+		c.check(value, typ.Elem(), path)
+	case reflect.Interface:
+		if typ.NumMethod() > 0 {
+			c.checkLeaf(value, typ, path)
+		}
+	case reflect.Struct:
+		object, ok := value.(map[string]any)
+		if !ok {
+			c.errs.Add(path, typeError(typ))
+			return
+		}
+		c.checkStruct(object, typ, path)
+	case reflect.Map:
+		object, ok := value.(map[string]any)
+		if !ok {
+			c.errs.Add(path, typeError(typ))
+			return
+		}
+		for key, fieldValue := range object {
+			c.check(fieldValue, typ.Elem(), append(path[:len(path):len(path)], key))
+		}
+	case reflect.Slice, reflect.Array:
+		// The byte slices are decoded from base64 strings
+		if typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8 {
+			c.checkLeaf(value, typ, path)
+			return
+		}
+
+		array, ok := value.([]any)
+		if !ok {
+			c.errs.Add(path, typeError(typ))
+			return
+		}
+		for i, element := range array {
+			if typ.Kind() == reflect.Array && i >= typ.Len() {
+				break
+			}
+			c.check(element, typ.Elem(), gonethttprequest.IndexPath(path, i))
+		}
+	default:
+		c.checkLeaf(value, typ, path)
+	}
+}
+
+// checkStruct checks a decoded JSON object against its destination struct type, collecting the type errors, the
+// unknown fields and the missing required fields, including the ones sent as null
+//
+// Parameters:
+//
+//   - object: The decoded JSON object
+//   - typ: The destination struct type
+//   - path: The path segments of the object
+func (c *collector) checkStruct(object map[string]any, typ reflect.Type, path []string) {
+	fields := getStructFields(typ)
+	found := make(map[string]struct{}, len(fields))
+
+	for key, value := range object {
+		keyPath := append(path[:len(path):len(path)], key)
+
+		// Get the field, matching its name exactly or case-insensitively like encoding/json
+		var field *structField
+		for i := range fields {
+			if fields[i].name == key {
+				field = &fields[i]
+				break
+			}
+		}
+		if field == nil {
+			for i := range fields {
+				if strings.EqualFold(fields[i].name, key) {
+					field = &fields[i]
+					break
+				}
+			}
+		}
+
+		if field == nil {
+			if c.options.DisallowUnknownFields {
+				c.errs.Add(keyPath, fmt.Errorf(gonethttprequest.ErrUnknownField, key))
+			}
+			continue
+		}
+
+		// The required fields sent as null are missing, since the validator checks they're initialized
+		if value == nil && field.required {
+			continue
+		}
+		found[field.name] = struct{}{}
+
+		// The quoted fields are checked when decoding the body
+		if !field.quoted {
+			c.check(value, field.typ, keyPath)
+		}
+	}
+
+	// Check the missing required fields
+	if c.options.SkipRequiredFields {
+		return
+	}
+	for _, field := range fields {
+		if _, ok := found[field.name]; !ok && field.required {
+			c.errs.Add(
+				append(path[:len(path):len(path)], field.name),
+				fmt.Errorf(gonethttprequest.ErrRequiredField, field.name),
+			)
+		}
+	}
+}
+
+// collectErrors collects all the field errors of a JSON body decoded into a destination, instead of failing on the
+// first one
+//
+// Parameters:
+//
+//   - body: The JSON body
+//   - dest: The destination to decode the body into
+//   - options: The decoding options
+//
+// Returns:
+//
+//   - error: The *gonethttprequest.FieldsError with the field errors, the error of a malformed body, or nil if there
+//     are no errors
+func collectErrors(body []byte, dest any, options *Options) error {
+	// Decode the body into generic values
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+
+	// Check the decoded values against the destination type
+	destType := reflect.TypeOf(dest)
+	if destType == nil || destType.Kind() != reflect.Pointer {
+		return nil
+	}
+	c := &collector{options: options}
+	c.check(value, destType.Elem(), nil)
+	if c.errs.IsEmpty() {
+		return nil
+	}
+	return &c.errs
+}
@@ -17,6 +17,7 @@ type (
 	Decoder struct {
 		decoder gojsondecoder.Decoder
 		mode    *goflagsmode.Flag
+		options *Options
 	}
 )
 
@@ -51,6 +52,31 @@ func NewDecoder(
 	}, nil
 }
 
+// NewDecoderWithOptions creates a new JSON decoder that decodes the bodies following the options
+//
+// Parameters:
+//
+//   - mode: The flag mode
+//   - decoder: The JSON decoder, used to decode the bodies when the options are nil
+//   - options: The decoder options, can be overridden per route with OptionsMiddleware
+//
+// Returns:
+//
+//   - *Decoder: The decoder
+//   - error: The error if any
+func NewDecoderWithOptions(
+	mode *goflagsmode.Flag,
+	decoder gojsondecoder.Decoder,
+	options *Options,
+) (*Decoder, error) {
+	instance, err := NewDecoder(mode, decoder)
+	if err != nil {
+		return nil, err
+	}
+	instance.options = options
+	return instance, nil
+}
+
 // Decode decodes the JSON body from an any value and stores it in the destination
 //
 // Parameters:
@@ -93,10 +119,32 @@ func (d Decoder) DecodeReader(
 	reader io.Reader,
 	dest any,
 ) error {
-	if err := d.decoder.DecodeReader(
-		reader,
-		dest,
-	); err != nil {
+	return d.decodeReader(reader, dest, d.options)
+}
+
+// decodeReader decodes the JSON body following the options and stores it in the destination
+//
+// Parameters:
+//
+//   - reader: The reader to read the body from
+//   - dest: The destination to store the decoded body
+//   - options: The decoder options, if nil the body is decoded by the underlying decoder
+//
+// Returns:
+//
+//   - error: The error if any
+func (d Decoder) decodeReader(
+	reader io.Reader,
+	dest any,
+	options *Options,
+) error {
+	var err error
+	if options != nil {
+		err = d.decodeWithOptions(reader, dest, options)
+	} else {
+		err = d.decoder.DecodeReader(reader, dest)
+	}
+	if err != nil {
 		return gonethttprequest.BodyDecodeErrorHandler(err)
 	}
 	return nil
@@ -135,5 +183,11 @@ func (d Decoder) DecodeRequest(
 			http.StatusUnsupportedMediaType,
 		)
 	}
-	return d.DecodeReader(r.Body, dest)
+
+	// Get the options of the route, if any
+	options := GetCtxOptions(r)
+	if options == nil {
+		options = d.options
+	}
+	return d.decodeReader(r.Body, dest, options)
 }
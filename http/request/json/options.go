@@ -0,0 +1,289 @@
+package json
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	gojsondecoder "github.com/ralvarezdev/go-json/decoder"
+
+	gonethttpctx "github.com/ralvarezdev/go-net/http/context"
+	gonethttprequest "github.com/ralvarezdev/go-net/http/request"
+)
+
+type (
+	// Options are the options to decode the JSON bodies
+	Options struct {
+		// DisallowUnknownFields rejects the bodies with fields that don't match the destination
+		DisallowUnknownFields bool
+
+		// UseNumber decodes the numbers into an any value as json.Number instead of float64. Since the injected
+		// decoder can't be configured to do it, the bodies are decoded with encoding/json when it's set
+		UseNumber bool
+
+		// DisallowDuplicateKeys rejects the bodies with objects that contain the same key more than once
+		DisallowDuplicateKeys bool
+
+		// MaxDepth is the maximum nesting depth of the objects and arrays, if zero the depth is not limited
+		MaxDepth int
+
+		// CollectAllErrors reports all the type mismatches, unknown fields and missing required fields of the body
+		// at once, instead of failing on the first one
+		CollectAllErrors bool
+
+		// SkipRequiredFields doesn't report the missing required fields when collecting all the errors, leaving them
+		// to the validator, e.g. for the documents encoded from an existing value, whose nil fields are null
+		SkipRequiredFields bool
+	}
+
+	// scanFrame is an object or array being scanned
+	scanFrame struct {
+		object    bool
+		expectKey bool
+		key       string
+		index     int
+		path      string
+		keys      map[string]struct{}
+	}
+)
+
+// NewStrictOptions creates the options that reject unknown fields and duplicate keys, and decode numbers as
+// json.Number
+//
+// Parameters:
+//
+//   - maxDepth: The maximum nesting depth, if zero the depth is not limited
+//
+// Returns:
+//
+//   - *Options: The strict options
+func NewStrictOptions(maxDepth int) *Options {
+	return &Options{
+		DisallowUnknownFields: true,
+		UseNumber:             true,
+		DisallowDuplicateKeys: true,
+		MaxDepth:              maxDepth,
+	}
+}
+
+// childPath returns the path of the value being scanned inside the frame
+//
+// Returns:
+//
+//   - string: The value path
+func (f *scanFrame) childPath() string {
+	if !f.object {
+		return f.path + "[" + strconv.Itoa(f.index) + "]"
+	}
+	if f.path == "" {
+		return f.key
+	}
+	return f.path + "." + f.key
+}
+
+// valueDone marks the value being scanned inside the frame as done
+func (f *scanFrame) valueDone() {
+	if f.object {
+		f.expectKey = true
+	} else {
+		f.index++
+	}
+}
+
+// scan checks the duplicate keys and the nesting depth of a JSON body. The syntax errors are ignored, since they're
+// reported when decoding the body
+//
+// Parameters:
+//
+//   - body: The JSON body
+//   - options: The decoding options
+//
+// Returns:
+//
+//   - error: The error if any
+func scan(body []byte, options *Options) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var stack []*scanFrame
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil
+		}
+
+		// Get the frame being scanned
+		var top *scanFrame
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+
+		delim, isDelim := token.(json.Delim)
+		switch {
+		case isDelim && (delim == '{' || delim == '['):
+			// Check the nesting depth
+			if options.MaxDepth > 0 && len(stack) >= options.MaxDepth {
+				return &gonethttprequest.MaxDepthError{Path: top.childPath(), Limit: options.MaxDepth}
+			}
+
+			frame := &scanFrame{object: delim == '{', expectKey: delim == '{'}
+			if top != nil {
+				frame.path = top.childPath()
+			}
+			if frame.object && options.DisallowDuplicateKeys {
+				frame.keys = make(map[string]struct{})
+			}
+			stack = append(stack, frame)
+		case isDelim:
+			stack = stack[:len(stack)-1]
+			if len(stack) > 0 {
+				stack[len(stack)-1].valueDone()
+			}
+		case top != nil && top.object && top.expectKey:
+			// Check if the key is duplicated
+			top.key, _ = token.(string)
+			top.expectKey = false
+			if top.keys != nil {
+				if _, ok := top.keys[top.key]; ok {
+					return &gonethttprequest.DuplicateKeyError{Key: top.childPath()}
+				}
+				top.keys[top.key] = struct{}{}
+			}
+		case top != nil:
+			top.valueDone()
+		}
+	}
+}
+
+// checkUnknownFields checks if a JSON body contains fields that don't match the destination
+//
+// Parameters:
+//
+//   - body: The JSON body
+//   - dest: The destination of the body
+//
+// Returns:
+//
+//   - error: The error if any
+func checkUnknownFields(body []byte, dest any) error {
+	// The invalid destinations are reported by the decoder
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Pointer || destValue.IsNil() {
+		return nil
+	}
+
+	// Decode the body into a new value of the destination type
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(reflect.New(destValue.Type().Elem()).Interface())
+}
+
+// decodeWithOptions checks a JSON body following the options, and decodes it with the injected decoder
+//
+// Parameters:
+//
+//   - reader: The reader to read the body from
+//   - dest: The destination to store the decoded body
+//   - options: The decoding options
+//
+// Returns:
+//
+//   - error: The error if any
+func (d Decoder) decodeWithOptions(reader io.Reader, dest any, options *Options) error {
+	// Read the body
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+
+	// Check the duplicate keys and the nesting depth
+	if options.DisallowDuplicateKeys || options.MaxDepth > 0 {
+		if err = scan(body, options); err != nil {
+			return err
+		}
+	}
+
+	// Collect all the field errors, including the unknown fields
+	if options.CollectAllErrors {
+		if err = collectErrors(body, dest, options); err != nil {
+			return err
+		}
+	}
+	checkUnknown := options.DisallowUnknownFields && !options.CollectAllErrors
+
+	// Decode the numbers as json.Number with encoding/json, since the injected decoder can't be configured to do it
+	if options.UseNumber {
+		if dest == nil {
+			return gojsondecoder.ErrNilDestination
+		}
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		if checkUnknown {
+			decoder.DisallowUnknownFields()
+		}
+		return decoder.Decode(dest)
+	}
+
+	// Check the unknown fields, since the injected decoder can't be configured to reject them
+	if checkUnknown {
+		if err = checkUnknownFields(body, dest); err != nil {
+			return err
+		}
+	}
+	return d.decoder.DecodeReader(bytes.NewReader(body), dest)
+}
+
+// SetCtxOptions sets the JSON decoder options of the route in the context, overriding the ones of the decoder
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//   - options: The decoder options
+//
+// Returns:
+//
+//   - *http.Request: The HTTP request with the options set in the context
+func SetCtxOptions(r *http.Request, options *Options) *http.Request {
+	ctx := context.WithValue(r.Context(), gonethttpctx.CtxJSONDecoderOptionsKey, options)
+	return r.WithContext(ctx)
+}
+
+// GetCtxOptions tries to get the JSON decoder options of the route from the context
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//
+// Returns:
+//
+//   - *Options: The decoder options from the context, or nil if not found
+func GetCtxOptions(r *http.Request) *Options {
+	options, ok := r.Context().Value(gonethttpctx.CtxJSONDecoderOptionsKey).(*Options)
+	if !ok {
+		return nil
+	}
+	return options
+}
+
+// OptionsMiddleware overrides the JSON decoder options of the routes it's applied to
+//
+// Parameters:
+//
+//   - options: The decoder options
+//
+// Returns:
+//
+//   - func(next http.Handler) http.Handler: The middleware
+func OptionsMiddleware(options *Options) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				next.ServeHTTP(w, SetCtxOptions(r, options))
+			},
+		)
+	}
+}
@@ -0,0 +1,125 @@
+package request
+
+import (
+	"fmt"
+	"strconv"
+)
+
+type (
+	// DuplicateKeyError is the error returned when a JSON object contains the same key more than once
+	DuplicateKeyError struct {
+		// Key is the path of the duplicate key, e.g. 'user.name' or 'items[2].id'
+		Key string
+	}
+
+	// MaxDepthError is the error returned when a JSON body exceeds the maximum nesting depth
+	MaxDepthError struct {
+		// Path is the path of the value that exceeds the maximum nesting depth, e.g. 'user.address' or 'items[2]'
+		Path string
+
+		// Limit is the maximum nesting depth
+		Limit int
+	}
+
+	// FieldsError is the error returned when a JSON body contains invalid fields, with all the field errors keyed by
+	// their JSON path. The nested objects are nested maps, and the array elements are keyed with their index, e.g.
+	// 'items[3]', following the shape of the validator errors
+	FieldsError struct {
+		Data map[string]any
+	}
+)
+
+// Error returns the error message
+//
+// Returns:
+//
+//   - string: The error message
+func (e *DuplicateKeyError) Error() string {
+	return fmt.Sprintf(ErrDuplicateKey, e.Key)
+}
+
+// Error returns the error message
+//
+// Returns:
+//
+//   - string: The error message
+func (e *MaxDepthError) Error() string {
+	return fmt.Sprintf(ErrMaxDepthExceeded, e.Limit)
+}
+
+// Error returns the error message
+//
+// Returns:
+//
+//   - string: The error message
+func (e *FieldsError) Error() string {
+	return fmt.Sprintf(ErrInvalidFields, e.Data)
+}
+
+// IsEmpty checks if there are no field errors
+//
+// Returns:
+//
+//   - bool: True if there are no field errors, false otherwise
+func (e *FieldsError) IsEmpty() bool {
+	return e == nil || len(e.Data) == 0
+}
+
+// Add adds an error to the field with the given path
+//
+// Parameters:
+//
+//   - path: The path segments of the field, e.g. ['items[3]', 'price']
+//   - err: The field error
+func (e *FieldsError) Add(path []string, err error) {
+	if e == nil || len(path) == 0 || err == nil {
+		return
+	}
+	if e.Data == nil {
+		e.Data = make(map[string]any)
+	}
+
+	// Get the map of the field parent
+	data := e.Data
+	for _, segment := range path[:len(path)-1] {
+		nestedData, ok := data[segment].(map[string]any)
+		if !ok {
+			// Keep the errors of the parent field if it already has them
+			if _, hasErrors := data[segment]; hasErrors {
+				return
+			}
+			nestedData = make(map[string]any)
+			data[segment] = nestedData
+		}
+		data = nestedData
+	}
+
+	// Add the error to the field
+	field := path[len(path)-1]
+	if _, isNested := data[field].(map[string]any); isNested {
+		return
+	}
+	errs, _ := data[field].([]string)
+	data[field] = append(errs, err.Error())
+}
+
+// IndexPath returns the path of an array element
+//
+// Parameters:
+//
+//   - path: The path segments of the array
+//   - index: The element index
+//
+// Returns:
+//
+//   - []string: The path segments of the element
+func IndexPath(path []string, index int) []string {
+	indexSegment := "[" + strconv.Itoa(index) + "]"
+	elementPath := make([]string, len(path), len(path)+1)
+	copy(elementPath, path)
+	if len(elementPath) == 0 {
+		return append(elementPath, indexSegment)
+	}
+	elementPath[len(elementPath)-1] += indexSegment
+	return elementPath
+}
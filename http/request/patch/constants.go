@@ -0,0 +1,29 @@
+package patch
+
+const (
+	// MergePatchContentType is the content type of the JSON Merge Patch bodies (RFC 7396)
+	MergePatchContentType = "application/merge-patch+json"
+
+	// JSONPatchContentType is the content type of the JSON Patch bodies (RFC 6902)
+	JSONPatchContentType = "application/json-patch+json"
+)
+
+const (
+	// OperationAdd adds a value to an object or inserts it into an array
+	OperationAdd = "add"
+
+	// OperationRemove removes a value
+	OperationRemove = "remove"
+
+	// OperationReplace replaces an existing value
+	OperationReplace = "replace"
+
+	// OperationMove removes a value and adds it to another location
+	OperationMove = "move"
+
+	// OperationCopy copies a value to another location
+	OperationCopy = "copy"
+
+	// OperationTest tests that a value is equal to the given one
+	OperationTest = "test"
+)
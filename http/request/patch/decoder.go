@@ -0,0 +1,373 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+
+	gojsondecoderjson "github.com/ralvarezdev/go-json/decoder/json"
+	govalidatormappervalidator "github.com/ralvarezdev/go-validator/mapper/validator"
+
+	gonethttp "github.com/ralvarezdev/go-net/http"
+	gonethttprequest "github.com/ralvarezdev/go-net/http/request"
+	gonethttprequesthandler "github.com/ralvarezdev/go-net/http/request/handler"
+	gonethttprequestjson "github.com/ralvarezdev/go-net/http/request/json"
+	gonethttpresponse "github.com/ralvarezdev/go-net/http/response"
+)
+
+type (
+	// Decoder applies the JSON Merge Patch (RFC 7396) and JSON Patch (RFC 6902) bodies to the existing value of the
+	// destination, instead of decoding them into a new value
+	Decoder struct {
+		validatorFn govalidatormappervalidator.ValidateFn
+		decoder     *gonethttprequestjson.Decoder
+	}
+
+	// Result is the result of applying a patch
+	Result struct {
+		// Touched are the paths of the fields added, replaced or removed by the patch, e.g. 'items[3].price'
+		Touched []string
+	}
+)
+
+// NewDecoder creates a new patch decoder
+//
+// Parameters:
+//
+//   - validatorFn: The function to validate the patched value, can be nil to skip the validation
+//
+// Returns:
+//
+//   - *Decoder: The patch decoder
+func NewDecoder(validatorFn govalidatormappervalidator.ValidateFn) *Decoder {
+	// Decode the patched documents rejecting the unknown fields, and collecting all the field errors. The required
+	// fields are left to the validator, since the nil fields of the existing value are encoded as null
+	decoder, _ := gonethttprequestjson.NewDecoderWithOptions(
+		nil,
+		gojsondecoderjson.NewDecoder(),
+		&gonethttprequestjson.Options{
+			DisallowUnknownFields: true,
+			CollectAllErrors:      true,
+			SkipRequiredFields:    true,
+		},
+	)
+
+	return &Decoder{
+		validatorFn: validatorFn,
+		decoder:     decoder,
+	}
+}
+
+// IsTouched checks if a field was touched by the patch, directly or through one of its parents or children
+//
+// Parameters:
+//
+//   - path: The field path, e.g. 'address.city'
+//
+// Returns:
+//
+//   - bool: True if the field was touched, false otherwise
+func (r *Result) IsTouched(path string) bool {
+	if r == nil {
+		return false
+	}
+
+	isChild := func(child, parent string) bool {
+		return parent == "" || strings.HasPrefix(child, parent+".") || strings.HasPrefix(child, parent+"[")
+	}
+	for _, touchedPath := range r.Touched {
+		if touchedPath == path || isChild(touchedPath, path) || isChild(path, touchedPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// isJSONPatch checks if a body is a JSON Patch, which is an array of operations
+//
+// Parameters:
+//
+//   - body: The body
+//
+// Returns:
+//
+//   - bool: True if the body is a JSON Patch, false otherwise
+func isJSONPatch(body []byte) bool {
+	body = bytes.TrimSpace(body)
+	return len(body) > 0 && body[0] == '['
+}
+
+// apply applies a patch to the destination
+//
+// Parameters:
+//
+//   - body: The patch body
+//   - dest: The pointer to the value to patch
+//   - isJSONPatch: Whether the patch is a JSON Patch or a JSON Merge Patch
+//
+// Returns:
+//
+//   - *Result: The patch result
+//   - error: The error if any
+func (d Decoder) apply(body []byte, dest any, isJSONPatch bool) (*Result, error) {
+	// Check the destination
+	destValue := reflect.ValueOf(dest)
+	if !destValue.IsValid() || destValue.Kind() != reflect.Pointer || destValue.IsNil() {
+		return nil, gonethttpresponse.NewDebugErrorWithCode(
+			ErrNilDestination,
+			gonethttp.ErrInternalServerError,
+			gonethttprequest.ErrCodeInvalidBodyType,
+			http.StatusInternalServerError,
+		)
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, gonethttprequest.BodyDecodeErrorHandler(io.EOF)
+	}
+
+	// Get the current document
+	encodedDoc, err := json.Marshal(dest)
+	if err != nil {
+		return nil, gonethttpresponse.NewDebugErrorWithCode(
+			err,
+			gonethttp.ErrInternalServerError,
+			gonethttprequest.ErrCodeInvalidBodyType,
+			http.StatusInternalServerError,
+		)
+	}
+	doc, err := decodeValue(encodedDoc)
+	if err != nil {
+		return nil, gonethttprequest.BodyDecodeErrorHandler(err)
+	}
+
+	// Apply the patch
+	result := &Result{}
+	if isJSONPatch {
+		var operations []Operation
+		if err = json.Unmarshal(body, &operations); err != nil {
+			var unmarshalTypeError *json.UnmarshalTypeError
+			if errors.As(err, &unmarshalTypeError) && unmarshalTypeError.Field == "" {
+				return nil, gonethttpresponse.NewErrorWithCode(
+					ErrInvalidJSONPatch,
+					ErrCodeInvalidPatch,
+					http.StatusBadRequest,
+				)
+			}
+			return nil, gonethttprequest.BodyDecodeErrorHandler(err)
+		}
+		if doc, err = jsonPatch(doc, operations, &result.Touched); err != nil {
+			return nil, err
+		}
+	} else {
+		patch, decodeErr := decodeValue(body)
+		if decodeErr != nil {
+			return nil, gonethttprequest.BodyDecodeErrorHandler(decodeErr)
+		}
+		if _, ok := patch.(map[string]any); !ok {
+			return nil, gonethttpresponse.NewErrorWithCode(
+				ErrInvalidMergePatch,
+				ErrCodeInvalidPatch,
+				http.StatusBadRequest,
+			)
+		}
+		doc = mergePatch(doc, patch, "", &result.Touched)
+	}
+
+	// Decode the patched document into a copy of the destination, resetting its JSON fields so the removed ones are
+	// zeroed
+	if encodedDoc, err = json.Marshal(doc); err != nil {
+		return nil, gonethttprequest.BodyDecodeErrorHandler(err)
+	}
+	patchedValue := reflect.New(destValue.Elem().Type())
+	patchedValue.Elem().Set(destValue.Elem())
+	resetJSONFields(patchedValue.Elem())
+	if err = d.decoder.DecodeReader(bytes.NewReader(encodedDoc), patchedValue.Interface()); err != nil {
+		return nil, err
+	}
+
+	// Validate the patched value
+	if d.validatorFn != nil {
+		validations, validateErr := d.validatorFn(patchedValue.Interface())
+		if validateErr != nil {
+			return nil, gonethttpresponse.NewDebugErrorWithCode(
+				validateErr,
+				gonethttp.ErrInternalServerError,
+				gonethttprequesthandler.ErrCodeValidationFailed,
+				http.StatusInternalServerError,
+			)
+		}
+		if validations != nil {
+			return nil, gonethttpresponse.NewFailDataErrorWithCode(
+				validations,
+				gonethttprequesthandler.ErrCodeValidationFailed,
+				http.StatusBadRequest,
+			)
+		}
+	}
+
+	// Set the patched value
+	destValue.Elem().Set(patchedValue.Elem())
+	return result, nil
+}
+
+// resetJSONFields sets the fields encoded to JSON of a struct to their zero value, keeping the other ones
+//
+// Parameters:
+//
+//   - value: The value
+func resetJSONFields(value reflect.Value) {
+	if value.Kind() != reflect.Struct {
+		value.SetZero()
+		return
+	}
+
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		if (!field.IsExported() && !field.Anonymous) || field.Tag.Get("json") == "-" {
+			continue
+		}
+		if value.Field(i).CanSet() {
+			value.Field(i).SetZero()
+		}
+	}
+}
+
+// MergePatch applies a JSON Merge Patch (RFC 7396) body to the destination
+//
+// Parameters:
+//
+//   - body: The merge patch body
+//   - dest: The pointer to the value to patch
+//
+// Returns:
+//
+//   - *Result: The patch result
+//   - error: The error if any
+func (d Decoder) MergePatch(body []byte, dest any) (*Result, error) {
+	return d.apply(body, dest, false)
+}
+
+// JSONPatch applies a JSON Patch (RFC 6902) body to the destination
+//
+// Parameters:
+//
+//   - body: The JSON Patch body
+//   - dest: The pointer to the value to patch
+//
+// Returns:
+//
+//   - *Result: The patch result
+//   - error: The error if any
+func (d Decoder) JSONPatch(body []byte, dest any) (*Result, error) {
+	return d.apply(body, dest, true)
+}
+
+// PatchRequest applies the patch body of a request to the destination, following its content type
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//   - dest: The pointer to the value to patch
+//
+// Returns:
+//
+//   - *Result: The patch result
+//   - error: The error if any
+func (d Decoder) PatchRequest(r *http.Request, dest any) (*Result, error) {
+	// Check the request
+	if r == nil {
+		return nil, gonethttpresponse.NewDebugErrorWithCode(
+			gonethttprequest.ErrNilRequest,
+			gonethttp.ErrInternalServerError,
+			gonethttprequest.ErrCodeNilRequest,
+			http.StatusInternalServerError,
+		)
+	}
+
+	// Check the content type
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get(gonethttp.ContentType))
+	if mediaType != MergePatchContentType && mediaType != JSONPatchContentType {
+		return nil, gonethttpresponse.NewFailFieldErrorWithCode(
+			gonethttprequest.ErrInvalidContentTypeField,
+			ErrUnsupportedContentType,
+			ErrCodeUnsupportedContentType,
+			http.StatusUnsupportedMediaType,
+		)
+	}
+
+	// Read the body
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, gonethttprequest.BodyDecodeErrorHandler(err)
+	}
+	return d.apply(body, dest, mediaType == JSONPatchContentType)
+}
+
+// Decode applies a patch body to the destination, as a JSON Patch if it's an array of operations or as a JSON Merge
+// Patch otherwise
+//
+// Parameters:
+//
+//   - body: The patch body
+//   - dest: The pointer to the value to patch
+//
+// Returns:
+//
+//   - error: The error if any
+func (d Decoder) Decode(body any, dest any) error {
+	var encodedBody []byte
+	switch typedBody := body.(type) {
+	case []byte:
+		encodedBody = typedBody
+	case string:
+		encodedBody = []byte(typedBody)
+	default:
+		return d.DecodeReader(nil, dest)
+	}
+	_, err := d.apply(encodedBody, dest, isJSONPatch(encodedBody))
+	return err
+}
+
+// DecodeReader applies a patch body to the destination, as a JSON Patch if it's an array of operations or as a JSON
+// Merge Patch otherwise
+//
+// Parameters:
+//
+//   - reader: The reader to read the patch body from
+//   - dest: The pointer to the value to patch
+//
+// Returns:
+//
+//   - error: The error if any
+func (d Decoder) DecodeReader(reader io.Reader, dest any) error {
+	if reader == nil {
+		return gonethttprequest.BodyDecodeErrorHandler(io.EOF)
+	}
+
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return gonethttprequest.BodyDecodeErrorHandler(err)
+	}
+	_, err = d.apply(body, dest, isJSONPatch(body))
+	return err
+}
+
+// DecodeRequest applies the patch body of a request to the destination, following its content type
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//   - dest: The pointer to the value to patch
+//
+// Returns:
+//
+//   - error: The error if any
+func (d Decoder) DecodeRequest(r *http.Request, dest any) error {
+	_, err := d.PatchRequest(r, dest)
+	return err
+}
@@ -0,0 +1,59 @@
+package patch_test
+
+import (
+	"testing"
+
+	gonethttprequestpatch "github.com/ralvarezdev/go-net/http/request/patch"
+)
+
+type (
+	// resource is a resource with nil slice and pointer fields that are required for the validator
+	resource struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+		Nick *string  `json:"nick"`
+	}
+)
+
+// TestMergePatchNilFields checks that the nil fields of the existing value, encoded as null, aren't reported as
+// missing required fields
+//
+// Parameters:
+//
+//   - t: The test
+func TestMergePatchNilFields(t *testing.T) {
+	decoder := gonethttprequestpatch.NewDecoder(nil)
+	dest := &resource{Name: "a"}
+
+	result, err := decoder.MergePatch([]byte(`{"name":"b"}`), dest)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dest.Name != "b" || dest.Tags != nil || dest.Nick != nil {
+		t.Fatalf("unexpected patched value: %+v", dest)
+	}
+	if !result.IsTouched("name") || result.IsTouched("tags") {
+		t.Fatalf("unexpected touched paths: %v", result.Touched)
+	}
+}
+
+// TestJSONPatchNilFields checks that the nil fields of the existing value are kept when patching other fields with a
+// JSON Patch
+//
+// Parameters:
+//
+//   - t: The test
+func TestJSONPatchNilFields(t *testing.T) {
+	decoder := gonethttprequestpatch.NewDecoder(nil)
+	dest := &resource{Name: "a"}
+
+	if _, err := decoder.JSONPatch(
+		[]byte(`[{"op":"replace","path":"/name","value":"b"}]`),
+		dest,
+	); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dest.Name != "b" || dest.Tags != nil || dest.Nick != nil {
+		t.Fatalf("unexpected patched value: %+v", dest)
+	}
+}
@@ -0,0 +1,34 @@
+package patch
+
+import (
+	"errors"
+)
+
+var (
+	ErrCodeUnsupportedContentType string
+	ErrCodeInvalidPatch           string
+	ErrCodeInvalidOperation       string
+	ErrCodeTestFailed             string
+)
+
+const (
+	ErrUnsupportedOperation = "unsupported operation %s"
+	ErrPathNotFound         = "path %s does not exist"
+	ErrInvalidArrayIndex    = "invalid array index %s"
+	ErrMoveIntoChild        = "cannot move %s into one of its children"
+	ErrTestFailed           = "value at %s does not match the expected value"
+)
+
+var (
+	ErrUnsupportedContentType = errors.New(
+		"invalid content type, expected application/merge-patch+json or application/json-patch+json",
+	)
+	ErrNilDestination    = errors.New("destination must be a non-nil pointer")
+	ErrInvalidMergePatch = errors.New("merge patch must be a JSON object")
+	ErrInvalidJSONPatch  = errors.New("json patch must be an array of operations")
+	ErrMissingOperation  = errors.New("operation is required")
+	ErrInvalidPointer    = errors.New("path must be a JSON pointer, e.g. '/items/0/price'")
+	ErrMissingValue      = errors.New("value is required")
+	ErrMissingFrom       = errors.New("from is required")
+	ErrRemoveRoot        = errors.New("cannot remove the whole document")
+)
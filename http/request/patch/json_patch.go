@@ -0,0 +1,297 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	gonethttpresponse "github.com/ralvarezdev/go-net/http/response"
+)
+
+type (
+	// Operation is a JSON Patch (RFC 6902) operation
+	Operation struct {
+		Op    string          `json:"op"`
+		Path  *string         `json:"path"`
+		From  *string         `json:"from,omitempty"`
+		Value json.RawMessage `json:"value,omitempty"`
+	}
+)
+
+// operationError returns the fail field error of an operation member
+//
+// Parameters:
+//
+//   - index: The operation index
+//   - member: The operation member, e.g. 'path'
+//   - err: The error
+//   - errorCode: The error code
+//   - httpStatus: The HTTP status
+//
+// Returns:
+//
+//   - error: The fail field error
+func operationError(index int, member string, err error, errorCode string, httpStatus int) error {
+	return gonethttpresponse.NewFailFieldErrorWithCode(
+		"["+strconv.Itoa(index)+"]."+member,
+		err,
+		errorCode,
+		httpStatus,
+	)
+}
+
+// decodeValue decodes a JSON value, keeping the numbers as json.Number
+//
+// Parameters:
+//
+//   - data: The encoded value
+//
+// Returns:
+//
+//   - any: The decoded value
+//   - error: The error if any
+func decodeValue(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// equalValues checks if two JSON values are equal, comparing the numbers by their value
+//
+// Parameters:
+//
+//   - a: The first value
+//   - b: The second value
+//
+// Returns:
+//
+//   - bool: True if the values are equal, false otherwise
+func equalValues(a, b any) bool {
+	normalize := func(value any) any {
+		encodedValue, err := json.Marshal(value)
+		if err != nil {
+			return value
+		}
+		var normalizedValue any
+		if err = json.Unmarshal(encodedValue, &normalizedValue); err != nil {
+			return value
+		}
+		return normalizedValue
+	}
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
+
+// addValue adds a value to a document
+//
+// Parameters:
+//
+//   - doc: The document
+//   - pointer: The JSON pointer
+//   - tokens: The reference tokens
+//   - value: The value to add
+//
+// Returns:
+//
+//   - any: The updated document
+//   - error: The error if any
+func addValue(doc any, pointer string, tokens []string, value any) (any, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	return updateValue(
+		doc, pointer, tokens, func(parent any, token string) (any, error) {
+			switch typedParent := parent.(type) {
+			case map[string]any:
+				typedParent[token] = value
+				return typedParent, nil
+			case []any:
+				index, err := arrayIndex(token, len(typedParent), true)
+				if err != nil {
+					return nil, err
+				}
+				typedParent = append(typedParent, nil)
+				copy(typedParent[index+1:], typedParent[index:])
+				typedParent[index] = value
+				return typedParent, nil
+			default:
+				return nil, fmt.Errorf(ErrPathNotFound, pointer)
+			}
+		},
+	)
+}
+
+// removeValue removes a value from a document
+//
+// Parameters:
+//
+//   - doc: The document
+//   - pointer: The JSON pointer
+//   - tokens: The reference tokens
+//
+// Returns:
+//
+//   - any: The updated document
+//   - error: The error if any
+func removeValue(doc any, pointer string, tokens []string) (any, error) {
+	if len(tokens) == 0 {
+		return nil, ErrRemoveRoot
+	}
+
+	return updateValue(
+		doc, pointer, tokens, func(parent any, token string) (any, error) {
+			switch typedParent := parent.(type) {
+			case map[string]any:
+				if _, ok := typedParent[token]; !ok {
+					return nil, fmt.Errorf(ErrPathNotFound, pointer)
+				}
+				delete(typedParent, token)
+				return typedParent, nil
+			case []any:
+				index, err := arrayIndex(token, len(typedParent), false)
+				if err != nil {
+					return nil, err
+				}
+				return append(typedParent[:index], typedParent[index+1:]...), nil
+			default:
+				return nil, fmt.Errorf(ErrPathNotFound, pointer)
+			}
+		},
+	)
+}
+
+// jsonPatch applies a JSON Patch (RFC 6902) to a document, collecting the touched paths. The operations are applied
+// in order and the patch fails as a whole if one of them fails
+//
+// Parameters:
+//
+//   - doc: The document
+//   - operations: The patch operations
+//   - touched: The touched paths
+//
+// Returns:
+//
+//   - any: The patched document
+//   - error: The error if any
+func jsonPatch(doc any, operations []Operation, touched *[]string) (any, error) {
+	for i, operation := range operations {
+		// Check the operation members
+		if operation.Op == "" {
+			return nil, operationError(i, "op", ErrMissingOperation, ErrCodeInvalidOperation, http.StatusBadRequest)
+		}
+		if operation.Path == nil {
+			return nil, operationError(i, "path", ErrInvalidPointer, ErrCodeInvalidOperation, http.StatusBadRequest)
+		}
+		tokens, err := parsePointer(*operation.Path)
+		if err != nil {
+			return nil, operationError(i, "path", err, ErrCodeInvalidOperation, http.StatusBadRequest)
+		}
+
+		// Decode the operation value
+		var value any
+		switch operation.Op {
+		case OperationAdd, OperationReplace, OperationTest:
+			if operation.Value == nil {
+				return nil, operationError(i, "value", ErrMissingValue, ErrCodeInvalidOperation, http.StatusBadRequest)
+			}
+			if value, err = decodeValue(operation.Value); err != nil {
+				return nil, operationError(i, "value", err, ErrCodeInvalidOperation, http.StatusBadRequest)
+			}
+		}
+
+		// Get the source value of the move and copy operations
+		var fromTokens []string
+		switch operation.Op {
+		case OperationMove, OperationCopy:
+			if operation.From == nil {
+				return nil, operationError(i, "from", ErrMissingFrom, ErrCodeInvalidOperation, http.StatusBadRequest)
+			}
+			if fromTokens, err = parsePointer(*operation.From); err != nil {
+				return nil, operationError(i, "from", err, ErrCodeInvalidOperation, http.StatusBadRequest)
+			}
+			if value, err = getValue(doc, *operation.From, fromTokens); err != nil {
+				return nil, operationError(i, "from", err, ErrCodeInvalidOperation, http.StatusBadRequest)
+			}
+		}
+
+		// Apply the operation
+		path := *operation.Path
+		switch operation.Op {
+		case OperationAdd:
+			doc, err = addValue(doc, path, tokens, value)
+		case OperationRemove:
+			doc, err = removeValue(doc, path, tokens)
+		case OperationReplace:
+			if _, err = getValue(doc, path, tokens); err == nil {
+				if len(tokens) == 0 {
+					doc = value
+				} else {
+					doc, err = removeValue(doc, path, tokens)
+					if err == nil {
+						doc, err = addValue(doc, path, tokens, value)
+					}
+				}
+			}
+		case OperationMove:
+			from := *operation.From
+			if path != from && strings.HasPrefix(path, from+"/") {
+				return nil, operationError(
+					i,
+					"path",
+					fmt.Errorf(ErrMoveIntoChild, from),
+					ErrCodeInvalidOperation,
+					http.StatusBadRequest,
+				)
+			}
+			*touched = append(*touched, pointerPath(doc, fromTokens))
+			if doc, err = removeValue(doc, from, fromTokens); err == nil {
+				doc, err = addValue(doc, path, tokens, value)
+			}
+		case OperationCopy:
+			// Copy the value, so both locations don't share it
+			var encodedValue []byte
+			if encodedValue, err = json.Marshal(value); err == nil {
+				if value, err = decodeValue(encodedValue); err == nil {
+					doc, err = addValue(doc, path, tokens, value)
+				}
+			}
+		case OperationTest:
+			var currentValue any
+			if currentValue, err = getValue(doc, path, tokens); err == nil && !equalValues(currentValue, value) {
+				return nil, operationError(
+					i,
+					"value",
+					fmt.Errorf(ErrTestFailed, path),
+					ErrCodeTestFailed,
+					http.StatusConflict,
+				)
+			}
+		default:
+			return nil, operationError(
+				i,
+				"op",
+				fmt.Errorf(ErrUnsupportedOperation, operation.Op),
+				ErrCodeInvalidOperation,
+				http.StatusBadRequest,
+			)
+		}
+		if err != nil {
+			return nil, operationError(i, "path", err, ErrCodeInvalidOperation, http.StatusBadRequest)
+		}
+
+		// Add the touched path
+		if operation.Op != OperationTest {
+			*touched = append(*touched, pointerPath(doc, tokens))
+		}
+	}
+	return doc, nil
+}
@@ -0,0 +1,38 @@
+package patch
+
+// mergePatch applies a JSON Merge Patch (RFC 7396) to a document, collecting the touched paths
+//
+// Parameters:
+//
+//   - target: The document
+//   - patch: The merge patch
+//   - path: The path of the document
+//   - touched: The touched paths
+//
+// Returns:
+//
+//   - any: The patched document
+func mergePatch(target, patch any, path string, touched *[]string) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		*touched = append(*touched, path)
+		return patch
+	}
+
+	// Replace the target if it's not an object
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = make(map[string]any)
+	}
+
+	for key, value := range patchObject {
+		keyPath := fieldPath(path, key, false)
+		if value == nil {
+			delete(targetObject, key)
+			*touched = append(*touched, keyPath)
+			continue
+		}
+		targetObject[key] = mergePatch(targetObject[key], value, keyPath, touched)
+	}
+	return targetObject
+}
@@ -0,0 +1,200 @@
+package patch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parsePointer parses a JSON pointer (RFC 6901) into its reference tokens
+//
+// Parameters:
+//
+//   - pointer: The JSON pointer, e.g. '/items/0/price'
+//
+// Returns:
+//
+//   - []string: The reference tokens, empty for the whole document
+//   - error: The error if any
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, ErrInvalidPointer
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// fieldPath returns the path of a field, with the array elements keyed by their index, e.g. 'items[3].price'
+//
+// Parameters:
+//
+//   - parentPath: The path of the parent value
+//   - token: The reference token of the field
+//   - isArray: Whether the parent value is an array
+//
+// Returns:
+//
+//   - string: The field path
+func fieldPath(parentPath, token string, isArray bool) string {
+	switch {
+	case isArray:
+		return parentPath + "[" + token + "]"
+	case parentPath == "":
+		return token
+	default:
+		return parentPath + "." + token
+	}
+}
+
+// pointerPath returns the path of a JSON pointer inside a document, following the document to know which tokens are
+// array indexes
+//
+// Parameters:
+//
+//   - doc: The document
+//   - tokens: The reference tokens
+//
+// Returns:
+//
+//   - string: The path
+func pointerPath(doc any, tokens []string) string {
+	path := ""
+	node := doc
+	for _, token := range tokens {
+		switch typedNode := node.(type) {
+		case []any:
+			// The end of the array refers to the last element once it's added
+			if token == "-" {
+				token = strconv.Itoa(len(typedNode) - 1)
+			}
+			path = fieldPath(path, token, true)
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(typedNode) {
+				node = nil
+			} else {
+				node = typedNode[index]
+			}
+		case map[string]any:
+			path = fieldPath(path, token, false)
+			node = typedNode[token]
+		default:
+			path = fieldPath(path, token, false)
+			node = nil
+		}
+	}
+	return path
+}
+
+// arrayIndex parses the index of an array element
+//
+// Parameters:
+//
+//   - token: The reference token
+//   - length: The array length
+//   - allowEnd: Whether the index can be the array length, or '-', to append an element
+//
+// Returns:
+//
+//   - int: The index
+//   - error: The error if any
+func arrayIndex(token string, length int, allowEnd bool) (int, error) {
+	if token == "-" && allowEnd {
+		return length, nil
+	}
+
+	// The indexes can't have leading zeros or signs
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.ContainsAny(token, "+-") {
+		return 0, fmt.Errorf(ErrInvalidArrayIndex, token)
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || index > length || (index == length && !allowEnd) {
+		return 0, fmt.Errorf(ErrInvalidArrayIndex, token)
+	}
+	return index, nil
+}
+
+// getValue gets the value of a JSON pointer inside a document
+//
+// Parameters:
+//
+//   - doc: The document
+//   - pointer: The JSON pointer
+//   - tokens: The reference tokens
+//
+// Returns:
+//
+//   - any: The value
+//   - error: The error if any
+func getValue(doc any, pointer string, tokens []string) (any, error) {
+	node := doc
+	for _, token := range tokens {
+		switch typedNode := node.(type) {
+		case map[string]any:
+			value, ok := typedNode[token]
+			if !ok {
+				return nil, fmt.Errorf(ErrPathNotFound, pointer)
+			}
+			node = value
+		case []any:
+			index, err := arrayIndex(token, len(typedNode), false)
+			if err != nil {
+				return nil, err
+			}
+			node = typedNode[index]
+		default:
+			return nil, fmt.Errorf(ErrPathNotFound, pointer)
+		}
+	}
+	return node, nil
+}
+
+// updateValue updates the parent of the last reference token of a JSON pointer, returning the updated document
+//
+// Parameters:
+//
+//   - node: The current node
+//   - pointer: The JSON pointer
+//   - tokens: The remaining reference tokens, at least one
+//   - updateFn: The function that updates the parent with the last reference token, returning the updated parent
+//
+// Returns:
+//
+//   - any: The updated node
+//   - error: The error if any
+func updateValue(
+	node any,
+	pointer string,
+	tokens []string,
+	updateFn func(parent any, token string) (any, error),
+) (any, error) {
+	if len(tokens) == 1 {
+		return updateFn(node, tokens[0])
+	}
+
+	// Update the child node
+	child, err := getValue(node, pointer, tokens[:1])
+	if err != nil {
+		return nil, err
+	}
+	updatedChild, err := updateValue(child, pointer, tokens[1:], updateFn)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set the updated child node
+	switch typedNode := node.(type) {
+	case map[string]any:
+		typedNode[tokens[0]] = updatedChild
+	case []any:
+		index, _ := arrayIndex(tokens[0], len(typedNode), false)
+		typedNode[index] = updatedChild
+	}
+	return node, nil
+}
@@ -1,9 +1,13 @@
 package protojson
 
 import (
+	"bytes"
 	"io"
 	"net/http"
 
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
 	gojsondecoder "github.com/ralvarezdev/go-json/decoder"
 	gojsondecoderprotojson "github.com/ralvarezdev/go-json/decoder/protojson"
 
@@ -14,7 +18,15 @@ import (
 
 type (
 	Decoder struct {
-		decoder gojsondecoder.Decoder
+		decoder               gojsondecoder.Decoder
+		disallowUnknownFields bool
+	}
+
+	// Options are the options of the ProtoJSON decoder
+	Options struct {
+		// DisallowUnknownFields rejects the bodies with unknown fields when the destination is a proto message,
+		// instead of discarding them
+		DisallowUnknownFields bool
 	}
 )
 
@@ -35,6 +47,23 @@ func NewDecoder() *Decoder {
 	}
 }
 
+// NewDecoderWithOptions creates a new Decoder instance with the given options
+//
+// Parameters:
+//
+//   - options: The decoder options
+//
+// Returns:
+//
+//   - *Decoder: The decoder instance
+func NewDecoderWithOptions(options *Options) *Decoder {
+	decoder := NewDecoder()
+	if options != nil {
+		decoder.disallowUnknownFields = options.DisallowUnknownFields
+	}
+	return decoder
+}
+
 // Decode decodes the JSON body from an any value and stores it in the destination
 //
 // Parameters:
@@ -49,13 +78,17 @@ func (d Decoder) Decode(
 	body any,
 	dest any,
 ) error {
-	if err := d.decoder.Decode(
-		body,
-		dest,
-	); err != nil {
+	// Check the body
+	if body == nil {
+		return gonethttprequest.BodyDecodeErrorHandler(gojsondecoderprotojson.ErrNilReader)
+	}
+
+	// Get the body reader
+	reader, err := gojsondecoder.ToReader(body)
+	if err != nil {
 		return gonethttprequest.BodyDecodeErrorHandler(err)
 	}
-	return nil
+	return d.DecodeReader(reader, dest)
 }
 
 // DecodeReader  decodes a JSON body from a reader into a destination
@@ -72,15 +105,51 @@ func (d Decoder) DecodeReader(
 	reader io.Reader,
 	dest any,
 ) error {
-	if err := d.decoder.DecodeReader(
-		reader,
-		dest,
-	); err != nil {
+	// Check the reader
+	if reader == nil {
+		return gonethttprequest.BodyDecodeErrorHandler(gojsondecoder.ErrNilReader)
+	}
+
+	// Read the body, to get the field paths of the errors
+	body, err := io.ReadAll(reader)
+	if err != nil {
 		return gonethttprequest.BodyDecodeErrorHandler(err)
 	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return gonethttprequest.BodyDecodeErrorHandler(io.EOF)
+	}
+
+	// Check the unknown fields of the proto messages, since the injected decoder can't be configured to reject them
+	if message, ok := dest.(proto.Message); ok && d.disallowUnknownFields {
+		if err = checkUnknownFields(body, message); err != nil {
+			return DecodeErrorHandler(body, err)
+		}
+	}
+
+	if err = d.decoder.DecodeReader(bytes.NewReader(body), dest); err != nil {
+		return DecodeErrorHandler(body, err)
+	}
 	return nil
 }
 
+// checkUnknownFields checks if a JSON body has fields that don't match a proto message, by decoding it into a new
+// message of the same type, so the destination is only decoded by the injected decoder
+//
+// Parameters:
+//
+//   - body: The JSON body
+//   - message: The destination proto message
+//
+// Returns:
+//
+//   - error: The error if any
+func checkUnknownFields(body []byte, message proto.Message) error {
+	return protojson.UnmarshalOptions{AllowPartial: true}.Unmarshal(
+		body,
+		message.ProtoReflect().New().Interface(),
+	)
+}
+
 // DecodeRequest decodes a JSON body from an HTTP request into a destination
 //
 // Parameters:
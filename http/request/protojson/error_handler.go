@@ -0,0 +1,364 @@
+package protojson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	gonethttprequest "github.com/ralvarezdev/go-net/http/request"
+	gonethttpresponse "github.com/ralvarezdev/go-net/http/response"
+)
+
+type (
+	// pathFrame is an object or array being scanned to get the path of a position
+	pathFrame struct {
+		object    bool
+		expectKey bool
+		key       string
+		index     int
+		path      string
+	}
+
+	// decodeErrorKind is the kind of a protojson decoding error
+	decodeErrorKind int
+
+	// decodeError is a parsed protojson decoding error
+	decodeError struct {
+		// syntax indicates if it's a syntax error
+		syntax bool
+
+		// line and column are the position of the error, starting at 1
+		line   int
+		column int
+
+		// kind is the kind of the error message
+		kind decodeErrorKind
+
+		// matches are the submatches of the error message, starting with the whole message
+		matches []string
+	}
+)
+
+const (
+	// decodeErrorUnknown is the kind of the errors without a known message
+	decodeErrorUnknown decodeErrorKind = iota
+
+	// decodeErrorUnknownField is the kind of the unknown field errors
+	decodeErrorUnknownField
+
+	// decodeErrorDuplicateField is the kind of the duplicate field errors
+	decodeErrorDuplicateField
+
+	// decodeErrorInvalidEnum is the kind of the invalid enum value errors
+	decodeErrorInvalidEnum
+
+	// decodeErrorInvalidWellKnownType is the kind of the invalid value errors of the well-known types, like Timestamp
+	// or Duration
+	decodeErrorInvalidWellKnownType
+
+	// decodeErrorOneofConflict is the kind of the oneof conflict errors
+	decodeErrorOneofConflict
+
+	// decodeErrorInvalidValue is the kind of the invalid value errors of the scalar fields
+	decodeErrorInvalidValue
+)
+
+var (
+	// positionErrorRegex matches the protojson errors with a position, ignoring their prefix since its spacing is not
+	// stable
+	positionErrorRegex = regexp.MustCompile(`(syntax error )?\(line (\d+):(\d+)\): (.*)$`)
+
+	// messageRegexes match the messages of the protojson errors with a position, in the order they're checked
+	messageRegexes = []struct {
+		kind  decodeErrorKind
+		regex *regexp.Regexp
+	}{
+		{decodeErrorUnknownField, regexp.MustCompile(`^unknown field "?([^"]*)"?$`)},
+		{decodeErrorDuplicateField, regexp.MustCompile(`^duplicate field "?([^"]*)"?$`)},
+		{decodeErrorInvalidEnum, regexp.MustCompile(`^invalid value for enum field (\S+): (.*)$`)},
+		{
+			decodeErrorInvalidWellKnownType,
+			regexp.MustCompile(
+				`^(?:invalid (google\.protobuf\.\w+) value|(google\.protobuf\.\w+) value out of range:) (.*)$`,
+			),
+		},
+		{decodeErrorOneofConflict, regexp.MustCompile(`^error parsing "?([^",]*)"?, oneof (\S+) is already set$`)},
+		{decodeErrorInvalidValue, regexp.MustCompile(`^invalid value for (\S+) field (\S+): (.*)$`)},
+	}
+)
+
+// childPath returns the path of the value being scanned inside the frame
+//
+// Returns:
+//
+//   - string: The value path
+func (f *pathFrame) childPath() string {
+	if !f.object {
+		return f.path + "[" + strconv.Itoa(f.index) + "]"
+	}
+	if f.path == "" {
+		return f.key
+	}
+	return f.path + "." + f.key
+}
+
+// valueDone marks the value being scanned inside the frame as done
+func (f *pathFrame) valueDone() {
+	if f.object {
+		f.expectKey = true
+	} else {
+		f.index++
+	}
+}
+
+// positionOffset converts a protojson error position to a byte offset of the body
+//
+// Parameters:
+//
+//   - body: The JSON body
+//   - line: The line of the position, starting at 1
+//   - column: The column of the position in runes, starting at 1
+//
+// Returns:
+//
+//   - int: The byte offset, or -1 if the position is out of the body
+func positionOffset(body []byte, line, column int) int {
+	offset := 0
+	for ; line > 1; line-- {
+		i := bytes.IndexByte(body[offset:], '\n')
+		if i < 0 {
+			return -1
+		}
+		offset += i + 1
+	}
+	for ; column > 1; column-- {
+		if offset >= len(body) {
+			return -1
+		}
+		_, size := utf8.DecodeRune(body[offset:])
+		offset += size
+	}
+	return offset
+}
+
+// pathAt returns the JSON path of the key or value that starts at an offset of the body, e.g. 'items[3].price'
+//
+// Parameters:
+//
+//   - body: The JSON body
+//   - offset: The byte offset
+//
+// Returns:
+//
+//   - string: The JSON path, or an empty string if it's the root value or the offset is out of the body
+func pathAt(body []byte, offset int) string {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var stack []*pathFrame
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+
+		// Get the frame being scanned
+		var top *pathFrame
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+
+		// Check if the token contains the offset
+		isKey := top != nil && top.object && top.expectKey
+		if int(decoder.InputOffset()) > offset {
+			switch {
+			case top == nil:
+				return ""
+			case isKey:
+				top.key, _ = token.(string)
+			}
+			return top.childPath()
+		}
+
+		delim, isDelim := token.(json.Delim)
+		switch {
+		case isDelim && (delim == '{' || delim == '['):
+			frame := &pathFrame{object: delim == '{', expectKey: delim == '{'}
+			if top != nil {
+				frame.path = top.childPath()
+			}
+			stack = append(stack, frame)
+		case isDelim:
+			stack = stack[:len(stack)-1]
+			if len(stack) > 0 {
+				stack[len(stack)-1].valueDone()
+			}
+		case isKey:
+			top.key, _ = token.(string)
+			top.expectKey = false
+		case top != nil:
+			top.valueDone()
+		}
+	}
+}
+
+// parseDecodeError parses the text of a protojson decoding error, since protojson doesn't export typed errors. This is
+// the only place that depends on the text of its errors, whose patterns were checked against
+// google.golang.org/protobuf v1.36.10, so they must be checked again when upgrading it
+//
+// Parameters:
+//
+//   - err: The protojson error
+//
+// Returns:
+//
+//   - *decodeError: The parsed error, or nil if it has no position
+func parseDecodeError(err error) *decodeError {
+	positionMatches := positionErrorRegex.FindStringSubmatch(err.Error())
+	if positionMatches == nil {
+		return nil
+	}
+	line, _ := strconv.Atoi(positionMatches[2])
+	column, _ := strconv.Atoi(positionMatches[3])
+	parsedErr := &decodeError{
+		syntax: positionMatches[1] != "",
+		line:   line,
+		column: column,
+	}
+	if parsedErr.syntax {
+		return parsedErr
+	}
+
+	// Get the kind of the error message
+	message := positionMatches[4]
+	for _, messageRegex := range messageRegexes {
+		if matches := messageRegex.regex.FindStringSubmatch(message); matches != nil {
+			parsedErr.kind = messageRegex.kind
+			parsedErr.matches = matches
+			break
+		}
+	}
+	return parsedErr
+}
+
+// DecodeErrorHandler maps the protojson decoding errors to the responses errors, with the JSON path of the field
+// that failed when the body is given. Unknown fields, duplicate fields, invalid enum values, invalid well-known type
+// values, oneof conflicts and invalid scalar values produce a FailFieldError, the other errors are handled by
+// BodyDecodeErrorHandler
+//
+// Parameters:
+//
+//   - body: The JSON body, used to get the field paths. If nil, the field names of the errors are used
+//   - err: The error that occurred during decoding
+//
+// Returns:
+//
+//   - error: The mapped error
+func DecodeErrorHandler(body []byte, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	// Check if the error has a position
+	parsedErr := parseDecodeError(err)
+	if parsedErr == nil {
+		// Check if the body is truncated
+		if strings.HasSuffix(err.Error(), "unexpected EOF") {
+			return gonethttprequest.BodyDecodeErrorHandler(io.ErrUnexpectedEOF)
+		}
+		return gonethttprequest.BodyDecodeErrorHandler(err)
+	}
+
+	// Get the offset of the position
+	offset := -1
+	if body != nil {
+		offset = positionOffset(body, parsedErr.line, parsedErr.column)
+	}
+
+	// Check if the error is a syntax error
+	if parsedErr.syntax {
+		if offset < 0 {
+			offset = 0
+		}
+		return gonethttpresponse.NewErrorWithCode(
+			fmt.Errorf(gonethttprequest.ErrSyntaxError, offset),
+			gonethttprequest.ErrCodeSyntaxError,
+			http.StatusBadRequest,
+		)
+	}
+
+	// getPath returns the JSON path of the position, or the field name if the body is not given
+	getPath := func(fieldName string) string {
+		if offset >= 0 {
+			if path := pathAt(body, offset); path != "" {
+				return path
+			}
+		}
+		return fieldName
+	}
+
+	matches := parsedErr.matches
+	switch parsedErr.kind {
+	case decodeErrorUnknownField:
+		fieldName := matches[1]
+		return gonethttpresponse.NewFailFieldErrorWithCode(
+			getPath(fieldName),
+			fmt.Errorf(gonethttprequest.ErrUnknownField, fieldName),
+			gonethttprequest.ErrCodeUnknownField,
+			http.StatusBadRequest,
+		)
+	case decodeErrorDuplicateField:
+		path := getPath(matches[1])
+		return gonethttpresponse.NewFailFieldErrorWithCode(
+			path,
+			fmt.Errorf(gonethttprequest.ErrDuplicateKey, path),
+			gonethttprequest.ErrCodeDuplicateKey,
+			http.StatusBadRequest,
+		)
+	case decodeErrorInvalidEnum:
+		return gonethttpresponse.NewFailFieldErrorWithCode(
+			getPath(matches[1]),
+			fmt.Errorf(ErrInvalidEnumValue, matches[2]),
+			ErrCodeInvalidEnumValue,
+			http.StatusBadRequest,
+		)
+	case decodeErrorInvalidWellKnownType:
+		typeName := matches[1]
+		if typeName == "" {
+			typeName = matches[2]
+		}
+		typeName = strings.TrimPrefix(typeName, "google.protobuf.")
+		return gonethttpresponse.NewFailFieldErrorWithCode(
+			getPath(typeName),
+			fmt.Errorf(ErrInvalidWellKnownTypeValue, typeName, matches[3]),
+			ErrCodeInvalidWellKnownTypeValue,
+			http.StatusBadRequest,
+		)
+	case decodeErrorOneofConflict:
+		oneofName := matches[2]
+		if i := strings.LastIndexByte(oneofName, '.'); i >= 0 {
+			oneofName = oneofName[i+1:]
+		}
+		path := getPath(matches[1])
+		return gonethttpresponse.NewFailFieldErrorWithCode(
+			path,
+			fmt.Errorf(ErrOneofConflict, matches[1], oneofName),
+			ErrCodeOneofConflict,
+			http.StatusBadRequest,
+		)
+	case decodeErrorInvalidValue:
+		return gonethttpresponse.NewFailFieldErrorWithCode(
+			getPath(matches[2]),
+			fmt.Errorf(gonethttpresponse.ErrInvalidFieldValueType, matches[1]),
+			ErrCodeInvalidFieldValue,
+			http.StatusBadRequest,
+		)
+	}
+	return gonethttprequest.BodyDecodeErrorHandler(err)
+}
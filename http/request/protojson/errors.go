@@ -0,0 +1,25 @@
+package protojson
+
+import (
+	"errors"
+)
+
+var (
+	ErrCodeInvalidEnumValue          string
+	ErrCodeInvalidWellKnownTypeValue string
+	ErrCodeOneofConflict             string
+	ErrCodeInvalidFieldValue         string
+	ErrCodeInvalidFieldMask          string
+)
+
+const (
+	ErrInvalidEnumValue          = "invalid enum value %s"
+	ErrInvalidWellKnownTypeValue = "invalid %s value %s"
+	ErrOneofConflict             = "field %s cannot be set, another field of the oneof %s is already set"
+	ErrInvalidFieldMaskPath      = "invalid field mask path %s"
+	ErrFieldMaskFieldNotFound    = "field mask field %s not found on the request message"
+)
+
+var (
+	ErrNilFieldMaskMessage = errors.New("field mask message cannot be nil")
+)
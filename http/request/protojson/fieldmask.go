@@ -0,0 +1,198 @@
+package protojson
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	gonethttp "github.com/ralvarezdev/go-net/http"
+	gonethttpresponse "github.com/ralvarezdev/go-net/http/response"
+)
+
+const (
+	// FieldMaskWildcard is the field mask path that selects all the fields
+	FieldMaskWildcard = "*"
+)
+
+// fieldMaskError returns the fail field error of an invalid field mask path
+//
+// Parameters:
+//
+//   - field: The name of the field mask field or query parameter
+//   - path: The invalid path
+//
+// Returns:
+//
+//   - error: The fail field error
+func fieldMaskError(field, path string) error {
+	return gonethttpresponse.NewFailFieldErrorWithCode(
+		field,
+		fmt.Errorf(ErrInvalidFieldMaskPath, path),
+		ErrCodeInvalidFieldMask,
+		http.StatusBadRequest,
+	)
+}
+
+// normalizeFieldMaskPath validates a field mask path against a message descriptor, and returns it with the proto
+// field names. The path segments can be the proto names or the JSON names of the fields
+//
+// Parameters:
+//
+//   - path: The field mask path, e.g. 'author.display_name' or 'author.displayName'
+//   - descriptor: The message descriptor
+//
+// Returns:
+//
+//   - string: The path with the proto field names
+//   - bool: True if the path is valid, false otherwise
+func normalizeFieldMaskPath(path string, descriptor protoreflect.MessageDescriptor) (string, bool) {
+	segments := strings.Split(path, ".")
+	for i, segment := range segments {
+		if descriptor == nil {
+			return "", false
+		}
+
+		// Get the field by its proto name or its JSON name
+		fields := descriptor.Fields()
+		field := fields.ByName(protoreflect.Name(segment))
+		if field == nil {
+			field = fields.ByJSONName(segment)
+		}
+		if field == nil {
+			return "", false
+		}
+		segments[i] = string(field.Name())
+
+		// Only the singular message fields can have subpaths
+		descriptor = nil
+		if field.Message() != nil && !field.IsList() && !field.IsMap() {
+			descriptor = field.Message()
+		}
+	}
+	return strings.Join(segments, "."), true
+}
+
+// ValidateFieldMask validates the paths of a field mask against a message, normalizing them to the proto field names.
+// The wildcard path is only valid on its own
+//
+// Parameters:
+//
+//   - field: The name of the field mask field or query parameter, used on the errors
+//   - mask: The field mask
+//   - message: The message the field mask applies to
+//
+// Returns:
+//
+//   - error: A FailFieldError if a path is invalid
+func ValidateFieldMask(field string, mask *fieldmaskpb.FieldMask, message proto.Message) error {
+	if mask == nil {
+		return nil
+	}
+	if message == nil {
+		return gonethttpresponse.NewDebugErrorWithCode(
+			ErrNilFieldMaskMessage,
+			gonethttp.ErrInternalServerError,
+			ErrCodeInvalidFieldMask,
+			http.StatusInternalServerError,
+		)
+	}
+
+	descriptor := message.ProtoReflect().Descriptor()
+	for i, path := range mask.GetPaths() {
+		if path == FieldMaskWildcard {
+			if len(mask.GetPaths()) > 1 {
+				return fieldMaskError(field, path)
+			}
+			continue
+		}
+
+		normalizedPath, ok := normalizeFieldMaskPath(path, descriptor)
+		if !ok {
+			return fieldMaskError(field, path)
+		}
+		mask.Paths[i] = normalizedPath
+	}
+	return nil
+}
+
+// ParseFieldMask parses a field mask from a query parameter of the request, like 'read_mask' or 'update_mask', and
+// validates it against a message. The paths can be comma separated or repeated
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//   - parameter: The query parameter name
+//   - message: The message the field mask applies to
+//
+// Returns:
+//
+//   - *fieldmaskpb.FieldMask: The field mask, or nil if the parameter is not set
+//   - error: A FailFieldError if a path is invalid
+func ParseFieldMask(r *http.Request, parameter string, message proto.Message) (*fieldmaskpb.FieldMask, error) {
+	values, ok := r.URL.Query()[parameter]
+	if !ok {
+		return nil, nil
+	}
+
+	mask := &fieldmaskpb.FieldMask{}
+	for _, value := range values {
+		for path := range strings.SplitSeq(value, ",") {
+			if path = strings.TrimSpace(path); path != "" {
+				mask.Paths = append(mask.Paths, path)
+			}
+		}
+	}
+	if err := ValidateFieldMask(parameter, mask, message); err != nil {
+		return nil, err
+	}
+	return mask, nil
+}
+
+// GetFieldMask gets the field mask of a decoded request message, like its 'update_mask' field, and validates it
+// against the message it applies to
+//
+// Parameters:
+//
+//   - request: The decoded request message
+//   - fieldName: The proto name of the field mask field
+//   - message: The message the field mask applies to, e.g. the resource being updated
+//
+// Returns:
+//
+//   - *fieldmaskpb.FieldMask: The field mask, or nil if it's not set
+//   - error: The error if any
+func GetFieldMask(request proto.Message, fieldName string, message proto.Message) (*fieldmaskpb.FieldMask, error) {
+	if request == nil {
+		return nil, nil
+	}
+
+	// Get the field mask field
+	reflectedRequest := request.ProtoReflect()
+	field := reflectedRequest.Descriptor().Fields().ByName(protoreflect.Name(fieldName))
+	if field == nil || field.Message() == nil ||
+		field.Message().FullName() != (&fieldmaskpb.FieldMask{}).ProtoReflect().Descriptor().FullName() {
+		return nil, gonethttpresponse.NewDebugErrorWithCode(
+			fmt.Errorf(ErrFieldMaskFieldNotFound, fieldName),
+			gonethttp.ErrInternalServerError,
+			ErrCodeInvalidFieldMask,
+			http.StatusInternalServerError,
+		)
+	}
+	if !reflectedRequest.Has(field) {
+		return nil, nil
+	}
+
+	// Validate the field mask, reporting the errors with its JSON name
+	mask, ok := reflectedRequest.Get(field).Message().Interface().(*fieldmaskpb.FieldMask)
+	if !ok {
+		return nil, nil
+	}
+	if err := ValidateFieldMask(field.JSONName(), mask, message); err != nil {
+		return nil, err
+	}
+	return mask, nil
+}
@@ -0,0 +1,75 @@
+package query
+
+const (
+	// PageParameter is the query parameter of the page number, starting at 1
+	PageParameter = "page"
+
+	// LimitParameter is the query parameter of the page size
+	LimitParameter = "limit"
+
+	// CursorParameter is the query parameter of the opaque pagination cursor
+	CursorParameter = "cursor"
+
+	// SortParameter is the query parameter of the comma separated sort fields, prefixed with '-' to sort them in
+	// descending order, e.g. 'sort=-created_at,name'
+	SortParameter = "sort"
+)
+
+const (
+	// DefaultLimit is the default page size
+	DefaultLimit = 20
+
+	// DefaultMaxLimit is the default maximum page size
+	DefaultMaxLimit = 100
+)
+
+const (
+	// OperatorEqual matches the values equal to the given one
+	OperatorEqual Operator = "eq"
+
+	// OperatorNotEqual matches the values not equal to the given one
+	OperatorNotEqual Operator = "ne"
+
+	// OperatorGreaterThan matches the values greater than the given one
+	OperatorGreaterThan Operator = "gt"
+
+	// OperatorGreaterThanOrEqual matches the values greater than or equal to the given one
+	OperatorGreaterThanOrEqual Operator = "gte"
+
+	// OperatorLessThan matches the values less than the given one
+	OperatorLessThan Operator = "lt"
+
+	// OperatorLessThanOrEqual matches the values less than or equal to the given one
+	OperatorLessThanOrEqual Operator = "lte"
+
+	// OperatorIn matches the values in the comma separated list, e.g. 'status=in:active,pending'
+	OperatorIn Operator = "in"
+
+	// OperatorNotIn matches the values not in the comma separated list
+	OperatorNotIn Operator = "nin"
+
+	// OperatorContains matches the strings containing the given one
+	OperatorContains Operator = "contains"
+)
+
+const (
+	// TypeString is the type of the string filter values
+	TypeString FieldType = "string"
+
+	// TypeInt is the type of the integer filter values
+	TypeInt FieldType = "int"
+
+	// TypeFloat is the type of the floating point filter values
+	TypeFloat FieldType = "float"
+
+	// TypeBool is the type of the boolean filter values
+	TypeBool FieldType = "bool"
+
+	// TypeTime is the type of the RFC 3339 or date, e.g. '2006-01-02', filter values
+	TypeTime FieldType = "time"
+)
+
+const (
+	// DateLayout is the layout of the date filter values
+	DateLayout = "2006-01-02"
+)
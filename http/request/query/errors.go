@@ -0,0 +1,28 @@
+package query
+
+import (
+	"errors"
+)
+
+var (
+	ErrCodeInvalidQueryParameter  string
+	ErrCodeInvalidQueryParameters string
+)
+
+const (
+	ErrInvalidLimit        = "limit must be an integer between 1 and %d"
+	ErrUnknownSortField    = "unknown sort field %s"
+	ErrDuplicateSortField  = "duplicate sort field %s"
+	ErrUnsupportedOperator = "unsupported operator %s, the allowed operators are %s"
+	ErrInvalidFilterValue  = "invalid %s value %s"
+	ErrDuplicateParameter  = "parameter %s can only be set once"
+)
+
+var (
+	ErrNilSpec           = errors.New("query spec cannot be nil")
+	ErrInvalidPage       = errors.New("page must be a positive integer")
+	ErrPageAndCursor     = errors.New("page and cursor cannot be used together")
+	ErrSortingNotAllowed = errors.New("sorting is not allowed")
+	ErrEmptyFilterValue  = errors.New("filter value cannot be empty")
+	ErrEmptySortField    = errors.New("sort field cannot be empty")
+)
@@ -0,0 +1,241 @@
+package query
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	gonethttp "github.com/ralvarezdev/go-net/http"
+	gonethttpresponse "github.com/ralvarezdev/go-net/http/response"
+	gonethttpresponsejsend "github.com/ralvarezdev/go-net/http/response/jsend"
+)
+
+type (
+	// Pagination is the pagination metadata of a list response
+	Pagination struct {
+		// Page is the page number, omitted on cursor pagination
+		Page int `json:"page,omitempty"`
+
+		// Limit is the page size
+		Limit int `json:"limit"`
+
+		// Total is the number of elements, if known
+		Total *int64 `json:"total,omitempty"`
+
+		// TotalPages is the number of pages, if the total is known
+		TotalPages int `json:"total_pages,omitempty"`
+
+		// NextCursor is the cursor of the next page, on cursor pagination
+		NextCursor string `json:"next_cursor,omitempty"`
+
+		// PrevCursor is the cursor of the previous page, on cursor pagination
+		PrevCursor string `json:"prev_cursor,omitempty"`
+
+		// HasMore indicates if there are more elements after the page
+		HasMore bool `json:"has_more"`
+	}
+
+	// ListData is the data of a list response
+	ListData[T any] struct {
+		Items      T           `json:"items"`
+		Pagination *Pagination `json:"pagination"`
+	}
+)
+
+// NewOffsetPagination creates the pagination metadata of a page based query
+//
+// Parameters:
+//
+//   - query: The parsed query
+//   - total: The number of elements
+//
+// Returns:
+//
+//   - *Pagination: The pagination metadata
+func NewOffsetPagination(query *Query, total int64) *Pagination {
+	if query == nil {
+		return nil
+	}
+
+	pagination := &Pagination{
+		Page:    query.Page,
+		Limit:   query.Limit,
+		Total:   &total,
+		HasMore: int64(query.Offset()+query.Limit) < total,
+	}
+	if query.Limit > 0 {
+		pagination.TotalPages = int((total + int64(query.Limit) - 1) / int64(query.Limit))
+	}
+	return pagination
+}
+
+// NewCursorPagination creates the pagination metadata of a cursor based query
+//
+// Parameters:
+//
+//   - query: The parsed query
+//   - nextCursor: The cursor of the next page, empty if it's the last one
+//   - prevCursor: The cursor of the previous page, empty if it's the first one
+//
+// Returns:
+//
+//   - *Pagination: The pagination metadata
+func NewCursorPagination(query *Query, nextCursor, prevCursor string) *Pagination {
+	if query == nil {
+		return nil
+	}
+
+	return &Pagination{
+		Limit:      query.Limit,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+		HasMore:    nextCursor != "",
+	}
+}
+
+// requestURL returns the original URL of the request, before the route groups stripped their prefixes
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//
+// Returns:
+//
+//   - *url.URL: The original URL
+func requestURL(r *http.Request) *url.URL {
+	if r.RequestURI != "" {
+		if parsedURL, err := url.ParseRequestURI(r.RequestURI); err == nil {
+			return parsedURL
+		}
+	}
+	return r.URL
+}
+
+// pageURL returns the URL of another page, keeping the other query parameters
+//
+// Parameters:
+//
+//   - u: The request URL
+//   - parameter: The pagination parameter to set, the page or the cursor
+//   - value: The parameter value
+//
+// Returns:
+//
+//   - string: The page URL
+func pageURL(u *url.URL, parameter, value string) string {
+	pageURL := *u
+	parameters := pageURL.Query()
+	parameters.Del(PageParameter)
+	parameters.Del(CursorParameter)
+	parameters.Set(parameter, value)
+	pageURL.RawQuery = parameters.Encode()
+	return pageURL.String()
+}
+
+// Links returns the URLs of the pages related to the current one, keyed by their relation type: 'first', 'prev',
+// 'next' and 'last'
+//
+// Parameters:
+//
+//   - u: The request URL
+//
+// Returns:
+//
+//   - map[string]string: The page URLs
+func (p *Pagination) Links(u *url.URL) map[string]string {
+	if p == nil || u == nil {
+		return nil
+	}
+
+	links := make(map[string]string)
+	if p.Page == 0 {
+		// Cursor pagination
+		if p.PrevCursor != "" {
+			links["prev"] = pageURL(u, CursorParameter, p.PrevCursor)
+		}
+		if p.NextCursor != "" {
+			links["next"] = pageURL(u, CursorParameter, p.NextCursor)
+		}
+		return links
+	}
+
+	// Page pagination
+	links["first"] = pageURL(u, PageParameter, "1")
+	if p.Page > 1 {
+		links["prev"] = pageURL(u, PageParameter, strconv.Itoa(p.Page-1))
+	}
+	if p.HasMore {
+		links["next"] = pageURL(u, PageParameter, strconv.Itoa(p.Page+1))
+	}
+	if p.TotalPages > 0 {
+		links["last"] = pageURL(u, PageParameter, strconv.Itoa(p.TotalPages))
+	}
+	return links
+}
+
+// SetLinkHeaders sets the RFC 8288 Link headers of the pages related to the current one, built from the original
+// request URL, so they keep the prefixes stripped by the route groups
+//
+// Parameters:
+//
+//   - w: The HTTP response writer
+//   - r: The HTTP request
+//   - pagination: The pagination metadata
+func SetLinkHeaders(w http.ResponseWriter, r *http.Request, pagination *Pagination) {
+	if w == nil || r == nil || pagination == nil {
+		return
+	}
+
+	// Add the links in a fixed order
+	links := pagination.Links(requestURL(r))
+	for _, rel := range []string{"first", "prev", "next", "last"} {
+		if link, ok := links[rel]; ok {
+			w.Header().Add(gonethttp.Link, fmt.Sprintf("<%s>; rel=\"%s\"", link, rel))
+		}
+	}
+}
+
+// NewListResponse creates a JSend success response with the items and the pagination metadata of a list endpoint
+//
+// Parameters:
+//
+//   - items: The items of the page
+//   - pagination: The pagination metadata
+//
+// Returns:
+//
+//   - gonethttpresponse.Response: The response
+func NewListResponse[T any](items T, pagination *Pagination) gonethttpresponse.Response {
+	return gonethttpresponse.NewResponse(
+		gonethttpresponsejsend.NewSuccessBody(
+			&ListData[T]{
+				Items:      items,
+				Pagination: pagination,
+			},
+		),
+		http.StatusOK,
+	)
+}
+
+// NewListResponseWithLinks sets the Link headers and returns the JSend success response of a list endpoint
+//
+// Parameters:
+//
+//   - w: The HTTP response writer
+//   - r: The HTTP request
+//   - items: The items of the page
+//   - pagination: The pagination metadata
+//
+// Returns:
+//
+//   - gonethttpresponse.Response: The response
+func NewListResponseWithLinks[T any](
+	w http.ResponseWriter,
+	r *http.Request,
+	items T,
+	pagination *Pagination,
+) gonethttpresponse.Response {
+	SetLinkHeaders(w, r, pagination)
+	return NewListResponse(items, pagination)
+}
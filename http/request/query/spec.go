@@ -0,0 +1,502 @@
+package query
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	gonethttp "github.com/ralvarezdev/go-net/http"
+	gonethttpctx "github.com/ralvarezdev/go-net/http/context"
+	gonethttpresponse "github.com/ralvarezdev/go-net/http/response"
+)
+
+type (
+	// Operator is a filter operator
+	Operator string
+
+	// FieldType is the type of the values of a filter
+	FieldType string
+
+	// FilterSpec declares a filterable field
+	FilterSpec struct {
+		// Type is the type of the filter values, TypeString by default
+		Type FieldType
+
+		// Operators are the allowed operators. If empty, the operators supported by the type are allowed
+		Operators []Operator
+	}
+
+	// Spec declares the pagination, sorting and filtering parameters allowed by a list endpoint
+	Spec struct {
+		// DefaultLimit is the page size used when the limit is not set, DefaultLimit by default
+		DefaultLimit int
+
+		// MaxLimit is the maximum page size, DefaultMaxLimit by default
+		MaxLimit int
+
+		// SortFields are the fields that can be sorted
+		SortFields []string
+
+		// DefaultSort is the sort used when it's not set
+		DefaultSort []SortField
+
+		// Filters are the filterable fields, keyed by their query parameter
+		Filters map[string]*FilterSpec
+	}
+
+	// SortField is a sorted field
+	SortField struct {
+		Field      string
+		Descending bool
+	}
+
+	// Filter is a parsed filter, with its values converted to the type of its field
+	Filter struct {
+		Field    string
+		Operator Operator
+		Values   []any
+	}
+
+	// Query is the parsed pagination, sorting and filtering parameters of a request
+	Query struct {
+		// Page is the page number, starting at 1. It's 0 when the cursor is set
+		Page int
+
+		// Limit is the page size
+		Limit int
+
+		// Cursor is the opaque pagination cursor
+		Cursor string
+
+		// Sort are the sorted fields, in order
+		Sort []SortField
+
+		// Filters are the filters, in the order of their query parameters
+		Filters []Filter
+	}
+
+	// parameterErrors are the errors of the query parameters, keeping the first error of each parameter
+	parameterErrors struct {
+		parameters []string
+		errors     map[string]error
+	}
+)
+
+var (
+	// typeOperators are the operators supported by each type
+	typeOperators = map[FieldType][]Operator{
+		TypeString: {OperatorEqual, OperatorNotEqual, OperatorIn, OperatorNotIn, OperatorContains},
+		TypeInt: {
+			OperatorEqual, OperatorNotEqual, OperatorGreaterThan, OperatorGreaterThanOrEqual, OperatorLessThan,
+			OperatorLessThanOrEqual, OperatorIn, OperatorNotIn,
+		},
+		TypeFloat: {
+			OperatorEqual, OperatorNotEqual, OperatorGreaterThan, OperatorGreaterThanOrEqual, OperatorLessThan,
+			OperatorLessThanOrEqual,
+		},
+		TypeBool: {OperatorEqual, OperatorNotEqual},
+		TypeTime: {
+			OperatorEqual, OperatorNotEqual, OperatorGreaterThan, OperatorGreaterThanOrEqual, OperatorLessThan,
+			OperatorLessThanOrEqual,
+		},
+	}
+
+	// allOperators are all the operators, used to split the operator prefix of the filter values
+	allOperators = []Operator{
+		OperatorEqual, OperatorNotEqual, OperatorGreaterThan, OperatorGreaterThanOrEqual, OperatorLessThan,
+		OperatorLessThanOrEqual, OperatorIn, OperatorNotIn, OperatorContains,
+	}
+)
+
+// add adds the error of a parameter, if it doesn't have one yet
+//
+// Parameters:
+//
+//   - parameter: The query parameter
+//   - err: The error
+func (p *parameterErrors) add(parameter string, err error) {
+	if p.errors == nil {
+		p.errors = make(map[string]error)
+	}
+	if _, ok := p.errors[parameter]; ok {
+		return
+	}
+	p.parameters = append(p.parameters, parameter)
+	p.errors[parameter] = err
+}
+
+// err returns a FailFieldError if a single parameter is invalid, or a FailDataError keyed by parameter if several are
+//
+// Returns:
+//
+//   - error: The error, or nil if there are no errors
+func (p *parameterErrors) err() error {
+	switch len(p.parameters) {
+	case 0:
+		return nil
+	case 1:
+		parameter := p.parameters[0]
+		return gonethttpresponse.NewFailFieldErrorWithCode(
+			parameter,
+			p.errors[parameter],
+			ErrCodeInvalidQueryParameter,
+			http.StatusBadRequest,
+		)
+	}
+
+	data := make(map[string][]string, len(p.parameters))
+	for _, parameter := range p.parameters {
+		data[parameter] = []string{p.errors[parameter].Error()}
+	}
+	return gonethttpresponse.NewFailDataErrorWithCode(
+		data,
+		ErrCodeInvalidQueryParameters,
+		http.StatusBadRequest,
+	)
+}
+
+// singleValue gets the single value of a parameter
+//
+// Parameters:
+//
+//   - parameters: The query parameters
+//   - parameter: The query parameter
+//   - errs: The parameter errors
+//
+// Returns:
+//
+//   - string: The value, or an empty string if it's not set
+//   - bool: True if the parameter is set once, false otherwise
+func singleValue(parameters map[string][]string, parameter string, errs *parameterErrors) (string, bool) {
+	values := parameters[parameter]
+	switch len(values) {
+	case 0:
+		return "", false
+	case 1:
+		return values[0], true
+	default:
+		errs.add(parameter, fmt.Errorf(ErrDuplicateParameter, parameter))
+		return "", false
+	}
+}
+
+// parseValue parses a filter value to its type
+//
+// Parameters:
+//
+//   - fieldType: The field type
+//   - value: The value
+//
+// Returns:
+//
+//   - any: The parsed value
+//   - error: The error if any
+func parseValue(fieldType FieldType, value string) (any, error) {
+	if value == "" {
+		return nil, ErrEmptyFilterValue
+	}
+
+	var (
+		parsedValue any
+		err         error
+	)
+	switch fieldType {
+	case TypeInt:
+		parsedValue, err = strconv.ParseInt(value, 10, 64)
+	case TypeFloat:
+		parsedValue, err = strconv.ParseFloat(value, 64)
+	case TypeBool:
+		parsedValue, err = strconv.ParseBool(value)
+	case TypeTime:
+		if parsedValue, err = time.Parse(time.RFC3339, value); err != nil {
+			parsedValue, err = time.Parse(DateLayout, value)
+		}
+	default:
+		parsedValue = value
+	}
+	if err != nil {
+		return nil, fmt.Errorf(ErrInvalidFilterValue, fieldType, value)
+	}
+	return parsedValue, nil
+}
+
+// parseFilter parses a filter value, e.g. 'in:active,pending'. Values without an operator prefix use OperatorEqual
+//
+// Parameters:
+//
+//   - field: The filtered field
+//   - filterSpec: The filter spec
+//   - value: The filter value
+//
+// Returns:
+//
+//   - *Filter: The parsed filter
+//   - error: The error if any
+func parseFilter(field string, filterSpec *FilterSpec, value string) (*Filter, error) {
+	// Split the operator prefix
+	operator := OperatorEqual
+	if prefix, rest, found := strings.Cut(value, ":"); found && slices.Contains(allOperators, Operator(prefix)) {
+		operator = Operator(prefix)
+		value = rest
+	}
+
+	// Check the operator
+	fieldType := filterSpec.Type
+	if fieldType == "" {
+		fieldType = TypeString
+	}
+	operators := filterSpec.Operators
+	if len(operators) == 0 {
+		operators = typeOperators[fieldType]
+	}
+	if !slices.Contains(operators, operator) {
+		allowedOperators := make([]string, len(operators))
+		for i, allowedOperator := range operators {
+			allowedOperators[i] = string(allowedOperator)
+		}
+		return nil, fmt.Errorf(ErrUnsupportedOperator, operator, strings.Join(allowedOperators, ", "))
+	}
+
+	// Parse the values, only the list operators accept several ones
+	rawValues := []string{value}
+	if operator == OperatorIn || operator == OperatorNotIn {
+		rawValues = strings.Split(value, ",")
+	}
+	values := make([]any, len(rawValues))
+	for i, rawValue := range rawValues {
+		parsedValue, err := parseValue(fieldType, strings.TrimSpace(rawValue))
+		if err != nil {
+			return nil, err
+		}
+		values[i] = parsedValue
+	}
+
+	return &Filter{
+		Field:    field,
+		Operator: operator,
+		Values:   values,
+	}, nil
+}
+
+// parsePagination parses the page, limit and cursor parameters
+//
+// Parameters:
+//
+//   - parameters: The query parameters
+//   - query: The query to set the pagination to
+//   - errs: The parameter errors
+func (s Spec) parsePagination(parameters map[string][]string, query *Query, errs *parameterErrors) {
+	// Parse the limit
+	maxLimit := s.MaxLimit
+	if maxLimit <= 0 {
+		maxLimit = DefaultMaxLimit
+	}
+	query.Limit = s.DefaultLimit
+	if query.Limit <= 0 {
+		query.Limit = min(DefaultLimit, maxLimit)
+	}
+	if value, ok := singleValue(parameters, LimitParameter, errs); ok {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxLimit {
+			errs.add(LimitParameter, fmt.Errorf(ErrInvalidLimit, maxLimit))
+		} else {
+			query.Limit = limit
+		}
+	}
+
+	// Parse the page and the cursor, which are mutually exclusive
+	page, hasPage := singleValue(parameters, PageParameter, errs)
+	cursor, hasCursor := singleValue(parameters, CursorParameter, errs)
+	hasCursor = hasCursor && cursor != ""
+	switch {
+	case hasPage && hasCursor:
+		errs.add(CursorParameter, ErrPageAndCursor)
+	case hasCursor:
+		query.Cursor = cursor
+	default:
+		query.Page = 1
+		if hasPage {
+			parsedPage, err := strconv.Atoi(page)
+			if err != nil || parsedPage < 1 {
+				errs.add(PageParameter, ErrInvalidPage)
+			} else {
+				query.Page = parsedPage
+			}
+		}
+	}
+}
+
+// parseSort parses the sort parameter
+//
+// Parameters:
+//
+//   - parameters: The query parameters
+//   - query: The query to set the sort to
+//   - errs: The parameter errors
+func (s Spec) parseSort(parameters map[string][]string, query *Query, errs *parameterErrors) {
+	value, ok := singleValue(parameters, SortParameter, errs)
+	if !ok {
+		query.Sort = slices.Clone(s.DefaultSort)
+		return
+	}
+	if len(s.SortFields) == 0 {
+		errs.add(SortParameter, ErrSortingNotAllowed)
+		return
+	}
+
+	for rawField := range strings.SplitSeq(value, ",") {
+		sortField := SortField{Field: strings.TrimSpace(rawField)}
+		if after, found := strings.CutPrefix(sortField.Field, "-"); found {
+			sortField.Field = after
+			sortField.Descending = true
+		}
+
+		// Check the sort field
+		switch {
+		case sortField.Field == "":
+			errs.add(SortParameter, ErrEmptySortField)
+			return
+		case !slices.Contains(s.SortFields, sortField.Field):
+			errs.add(SortParameter, fmt.Errorf(ErrUnknownSortField, sortField.Field))
+			return
+		case slices.ContainsFunc(
+			query.Sort, func(field SortField) bool {
+				return field.Field == sortField.Field
+			},
+		):
+			errs.add(SortParameter, fmt.Errorf(ErrDuplicateSortField, sortField.Field))
+			return
+		}
+		query.Sort = append(query.Sort, sortField)
+	}
+}
+
+// parseFilters parses the filter parameters declared by the spec, ignoring the other parameters
+//
+// Parameters:
+//
+//   - parameters: The query parameters
+//   - query: The query to set the filters to
+//   - errs: The parameter errors
+func (s Spec) parseFilters(parameters map[string][]string, query *Query, errs *parameterErrors) {
+	// Parse the filters sorted by their parameter, so their order is deterministic
+	fields := make([]string, 0, len(s.Filters))
+	for field := range s.Filters {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+
+	for _, field := range fields {
+		filterSpec := s.Filters[field]
+		if filterSpec == nil {
+			continue
+		}
+
+		// A field can be filtered several times, e.g. 'price=gte:10&price=lte:20'
+		for _, value := range parameters[field] {
+			filter, err := parseFilter(field, filterSpec, value)
+			if err != nil {
+				errs.add(field, err)
+				break
+			}
+			query.Filters = append(query.Filters, *filter)
+		}
+	}
+}
+
+// Parse parses the pagination, sorting and filtering query parameters
+//
+// Parameters:
+//
+//   - parameters: The query parameters
+//
+// Returns:
+//
+//   - *Query: The parsed query
+//   - error: A FailFieldError if a single parameter is invalid, or a FailDataError keyed by parameter if several are
+func (s *Spec) Parse(parameters map[string][]string) (*Query, error) {
+	if s == nil {
+		return nil, gonethttpresponse.NewDebugErrorWithCode(
+			ErrNilSpec,
+			gonethttp.ErrInternalServerError,
+			ErrCodeInvalidQueryParameter,
+			http.StatusInternalServerError,
+		)
+	}
+
+	query := &Query{}
+	errs := &parameterErrors{}
+	s.parsePagination(parameters, query, errs)
+	s.parseSort(parameters, query, errs)
+	s.parseFilters(parameters, query, errs)
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+	return query, nil
+}
+
+// ParseRequest parses the pagination, sorting and filtering query parameters of a request, from the context if
+// they were set by the router, or from its URL otherwise
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//
+// Returns:
+//
+//   - *Query: The parsed query
+//   - error: A FailFieldError if a single parameter is invalid, or a FailDataError keyed by parameter if several are
+func (s *Spec) ParseRequest(r *http.Request) (*Query, error) {
+	parameters := gonethttpctx.GetCtxQueryParameters(r)
+	if parameters == nil {
+		parameters = r.URL.Query()
+	}
+	return s.Parse(parameters)
+}
+
+// Offset returns the offset of the first element of the page
+//
+// Returns:
+//
+//   - int: The offset, or 0 when the cursor is used
+func (q *Query) Offset() int {
+	if q == nil || q.Page < 1 {
+		return 0
+	}
+	return (q.Page - 1) * q.Limit
+}
+
+// FiltersOf returns the filters of a field
+//
+// Parameters:
+//
+//   - field: The field
+//
+// Returns:
+//
+//   - []Filter: The filters of the field
+func (q *Query) FiltersOf(field string) []Filter {
+	if q == nil {
+		return nil
+	}
+
+	var filters []Filter
+	for _, filter := range q.Filters {
+		if filter.Field == field {
+			filters = append(filters, filter)
+		}
+	}
+	return filters
+}
+
+// IsCursorQuery checks if the query uses the cursor pagination
+//
+// Returns:
+//
+//   - bool: True if the query uses the cursor, false otherwise
+func (q *Query) IsCursorQuery() bool {
+	return q != nil && q.Cursor != ""
+}
@@ -0,0 +1,168 @@
+package protojson
+
+import (
+	"reflect"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+type (
+	// fieldMaskTree is the tree of the field mask paths, where a nil subtree selects the whole field
+	fieldMaskTree map[string]fieldMaskTree
+)
+
+// newFieldMaskTree creates the tree of the field mask paths
+//
+// Parameters:
+//
+//   - mask: The field mask, with the proto field names
+//
+// Returns:
+//
+//   - fieldMaskTree: The field mask tree, or nil if the mask selects all the fields
+func newFieldMaskTree(mask *fieldmaskpb.FieldMask) fieldMaskTree {
+	tree := fieldMaskTree{}
+	for _, path := range mask.GetPaths() {
+		if path == "*" {
+			return nil
+		}
+
+		node := tree
+		segments := strings.Split(path, ".")
+		for i, segment := range segments {
+			child, ok := node[segment]
+			if ok && child == nil {
+				// The whole field is already selected
+				break
+			}
+			if i == len(segments)-1 {
+				node[segment] = nil
+				break
+			}
+			if !ok {
+				child = fieldMaskTree{}
+				node[segment] = child
+			}
+			node = child
+		}
+	}
+	return tree
+}
+
+// prune clears the fields of a message that are not selected by the field mask tree
+//
+// Parameters:
+//
+//   - message: The reflected message
+func (t fieldMaskTree) prune(message protoreflect.Message) {
+	message.Range(
+		func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+			subtree, ok := t[string(field.Name())]
+			switch {
+			case !ok:
+				message.Clear(field)
+			case subtree != nil && field.Message() != nil && !field.IsList() && !field.IsMap():
+				subtree.prune(value.Message())
+			}
+			return true
+		},
+	)
+}
+
+// ApplyFieldMask returns a copy of the message with only the fields selected by the field mask, like a 'read_mask'.
+// The given message is not modified
+//
+// Parameters:
+//
+//   - message: The message
+//   - mask: The field mask, with the proto field names. If nil, the message is returned as is
+//
+// Returns:
+//
+//   - proto.Message: The pruned message
+func ApplyFieldMask(message proto.Message, mask *fieldmaskpb.FieldMask) proto.Message {
+	if message == nil || mask == nil {
+		return message
+	}
+
+	tree := newFieldMaskTree(mask)
+	if tree == nil {
+		return message
+	}
+	prunedMessage := proto.Clone(message)
+	tree.prune(prunedMessage.ProtoReflect())
+	return prunedMessage
+}
+
+// applyFieldMaskToBody returns a copy of the body with the field mask applied to its messages. The body can be a
+// message, or a struct, or a pointer to a struct, whose message fields are pruned
+//
+// Parameters:
+//
+//   - body: The body
+//   - mask: The field mask
+//
+// Returns:
+//
+//   - any: The pruned body
+func applyFieldMaskToBody(body any, mask *fieldmaskpb.FieldMask) any {
+	if message, ok := body.(proto.Message); ok {
+		return ApplyFieldMask(message, mask)
+	}
+
+	// Get the struct value
+	bodyValue := reflect.ValueOf(body)
+	isPointer := bodyValue.Kind() == reflect.Pointer
+	if isPointer {
+		if bodyValue.IsNil() {
+			return body
+		}
+		bodyValue = bodyValue.Elem()
+	}
+	if bodyValue.Kind() != reflect.Struct {
+		return body
+	}
+
+	// Copy the struct, replacing its message fields with the pruned ones
+	messageType := reflect.TypeFor[proto.Message]()
+	prunedValue := reflect.New(bodyValue.Type()).Elem()
+	prunedValue.Set(bodyValue)
+	for i := 0; i < prunedValue.NumField(); i++ {
+		field := prunedValue.Field(i)
+		if !field.CanSet() || !field.Type().Implements(messageType) {
+			continue
+		}
+		if field.Kind() == reflect.Pointer && field.IsNil() {
+			continue
+		}
+		if message, ok := field.Interface().(proto.Message); ok {
+			field.Set(reflect.ValueOf(ApplyFieldMask(message, mask)))
+		}
+	}
+
+	if isPointer {
+		return prunedValue.Addr().Interface()
+	}
+	return prunedValue.Interface()
+}
+
+// PrecomputeMarshalWithFieldMask precomputes the marshaled body, returning only the fields of its messages selected
+// by the field mask
+//
+// Parameters:
+//
+//   - body: The body to precompute the marshaled body for
+//   - mask: The field mask, with the proto field names. If nil, all the fields are returned
+//
+// Returns:
+//
+//   - (map[string]any, error): The precomputed marshaled body and the error if any
+func (e Encoder) PrecomputeMarshalWithFieldMask(
+	body any,
+	mask *fieldmaskpb.FieldMask,
+) (map[string]any, error) {
+	return e.PrecomputeMarshal(applyFieldMaskToBody(body, mask))
+}
@@ -0,0 +1,191 @@
+package route
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// Compile flattens the router tree into a single multiplexer, registering every route of the sub-routers with its
+// full pattern and its precomputed middlewares chain. The matched requests skip the nested multiplexers, so they
+// keep their original path, while the unmatched ones are served by the nested router tree, keeping its not found,
+// method not allowed and automatic methods responses. The router must be fully loaded before compiling it, the
+// routes added afterward are not served by the compiled handler
+//
+// Since the matched requests keep their original path, the sub-router middlewares see the full path in r.URL.Path,
+// e.g. '/api/v1/users/42', while on the nested router tree they see the path stripped of the parent prefixes, e.g.
+// '/users/42'. The middlewares that match paths should use the original request path, as SkipPaths does, to behave
+// the same in both modes
+//
+// The sub-routers with a normalization policy are not flattened, so their requests, and the ones of their nested
+// sub-routers, are still served by the nested router tree, which normalizes their paths before routing them
+//
+// Returns:
+//
+//   - http.Handler: The compiled handler
+//   - error: The error if any
+func (r *Router) Compile() (http.Handler, error) {
+	if r == nil {
+		return nil, ErrNilRouter
+	}
+
+	// Create the compiled router, inheriting the router settings
+	compiled := &Router{
+		middlewares:               r.middlewares,
+		mux:                       http.NewServeMux(),
+		pattern:                   r.pattern,
+		relativePath:              r.relativePath,
+		fullPath:                  r.fullPath,
+		method:                    r.method,
+		handler:                   r.handler,
+		mode:                      r.mode,
+		logger:                    r.logger,
+		routers:                   r.routers,
+		names:                     r.names,
+		notFoundHandlerFn:         r.notFoundHandlerFn,
+		methodNotAllowedHandlerFn: r.methodNotAllowedHandlerFn,
+		disableAutomaticOptions:   r.disableAutomaticOptions,
+		disableAutomaticHead:      r.disableAutomaticHead,
+		normalization:             r.normalization,
+	}
+
+	// Register the routes of the router as they are, the route groups also serve the unmatched requests of their
+	// sub-routers
+	for _, route := range r.routes {
+		if err := compiled.register(route.Pattern, route.handler); err != nil {
+			return nil, err
+		}
+		compiled.indexRoute(route.Pattern, route)
+
+		// Flatten the routes of the sub-routers without normalization policy
+		if subRouter, ok := route.router.(*Router); ok && subRouter.normalization == nil {
+			if err := compiled.flatten(
+				subRouter,
+				strings.TrimSuffix(route.FullPath, "/"),
+				nil,
+			); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Chain the handlers, the router middlewares are applied to every request after setting the route in the context
+	compiled.firstHandler = compiled.setCtxRouteMiddleware(
+		ChainHandlers(
+			r.namedMiddleware(http.HandlerFunc(compiled.serveHTTP)),
+			compiled.middlewares...,
+		),
+	)
+
+	if compiled.logger != nil && compiled.mode != nil && compiled.mode.IsDebug() {
+		compiled.logger.Debug(
+			"Compiled router",
+			slog.String("full_path", compiled.fullPath),
+			slog.Int("routes", len(compiled.routes)),
+		)
+	}
+	return compiled.firstHandler, nil
+}
+
+// flatten registers the routes of a sub-router with their full pattern, chaining the middlewares and the named
+// middlewares of the sub-routers between the compiled router and the route
+//
+// Parameters:
+//
+//   - subRouter: The sub-router
+//   - prefix: The full path prefix of the sub-router routes
+//   - middlewares: The middlewares of the parent sub-routers
+//
+// Returns:
+//
+//   - error: The error if any
+func (r *Router) flatten(
+	subRouter *Router,
+	prefix string,
+	middlewares []func(http.Handler) http.Handler,
+) error {
+	// Add the sub-router middlewares and named middlewares, without modifying the parent slice
+	chain := make(
+		[]func(http.Handler) http.Handler,
+		0,
+		len(middlewares)+len(subRouter.middlewares)+1,
+	)
+	chain = append(chain, middlewares...)
+	chain = append(chain, subRouter.middlewares...)
+	chain = append(chain, subRouter.namedMiddleware)
+
+	for _, route := range subRouter.routes {
+		path := joinRoutePath(prefix, route.Path)
+
+		// Flatten the routes of the nested sub-routers, the ones with a normalization policy are served by the nested
+		// router tree, since their paths must be normalized before routing them
+		if nestedRouter, ok := route.router.(*Router); ok {
+			if nestedRouter.normalization != nil {
+				continue
+			}
+			if err := r.flatten(
+				nestedRouter,
+				strings.TrimSuffix(path, "/"),
+				chain,
+			); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// The route groups and static files are served by the nested router tree, since they strip their prefix
+		if route.mount || route.router != nil {
+			continue
+		}
+
+		// Register the route with its full pattern
+		pattern := path
+		if route.Method != "" {
+			pattern = route.Method + " " + path
+		}
+		if err := r.register(pattern, ChainHandlers(route.handler, chain...)); err != nil {
+			return err
+		}
+
+		// Index the original route by its full pattern, so the context gets the same route as on the nested router tree
+		if r.patterns == nil {
+			r.patterns = make(map[string]*Route)
+		}
+		r.patterns[pattern] = route
+		r.routes = append(
+			r.routes, &Route{
+				Method:               route.Method,
+				Path:                 path,
+				Pattern:              pattern,
+				FullPath:             path,
+				Name:                 route.Name,
+				Metadata:             route.Metadata,
+				handler:              route.handler,
+				exclusions:           route.exclusions,
+				disableAutomaticHead: route.disableAutomaticHead,
+			},
+		)
+	}
+	return nil
+}
+
+// register registers a handler in the multiplexer, returning the patterns conflicts as errors
+//
+// Parameters:
+//
+//   - pattern: The pattern of the handler
+//   - handler: The handler
+//
+// Returns:
+//
+//   - error: The error if any
+func (r *Router) register(pattern string, handler http.Handler) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf(ErrCompileRoute, pattern, recovered)
+		}
+	}()
+	r.mux.Handle(pattern, handler)
+	return nil
+}
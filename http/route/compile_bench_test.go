@@ -0,0 +1,101 @@
+package route_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	goflagsmode "github.com/ralvarezdev/go-flags/mode"
+
+	gonethttphandlerjsonjsend "github.com/ralvarezdev/go-net/http/handler/json/jsend"
+	gonethttproute "github.com/ralvarezdev/go-net/http/route"
+)
+
+// newBenchmarkRouter creates a router with a route three routers deep, '/api/v1/users/{id}', each router with its
+// own middleware
+//
+// Parameters:
+//
+//   - b: The benchmark
+//
+// Returns:
+//
+//   - gonethttproute.RouterWrapper: The base router
+func newBenchmarkRouter(b *testing.B) gonethttproute.RouterWrapper {
+	b.Helper()
+
+	mode := goflagsmode.NewFlag(goflagsmode.Prod, []goflagsmode.Mode{goflagsmode.Prod})
+	handler, err := gonethttphandlerjsonjsend.NewHandler(mode, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	middleware := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Benchmark", "true")
+				next.ServeHTTP(w, r)
+			},
+		)
+	}
+
+	// Nest the routers
+	router, err := gonethttproute.NewBaseRouter(mode, handler, nil, middleware)
+	if err != nil {
+		b.Fatal(err)
+	}
+	subRouter := router
+	for _, pattern := range []string{"/api", "/v1", "/users"} {
+		if subRouter, err = subRouter.NewRouter(pattern, middleware); err != nil {
+			b.Fatal(err)
+		}
+	}
+	subRouter.AddHandleFunc(
+		"GET /{id}", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(r.PathValue("id")))
+		},
+	)
+	return router
+}
+
+// serveBenchmark serves a GET request to the route three routers deep
+//
+// Parameters:
+//
+//   - b: The benchmark
+//   - handler: The handler to serve the request
+func serveBenchmark(b *testing.B, handler http.Handler) {
+	b.Helper()
+
+	// Check the route is matched
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/users/42", nil)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK || recorder.Body.String() != "42" {
+		b.Fatalf("unexpected response: %d %q", recorder.Code, recorder.Body.String())
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for b.Loop() {
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+	}
+}
+
+// BenchmarkServeHTTP compares serving a route three routers deep through the nested multiplexers and through the
+// compiled one
+func BenchmarkServeHTTP(b *testing.B) {
+	b.Run(
+		"Nested", func(b *testing.B) {
+			serveBenchmark(b, newBenchmarkRouter(b).Handler())
+		},
+	)
+	b.Run(
+		"Compiled", func(b *testing.B) {
+			compiled, err := newBenchmarkRouter(b).Compile()
+			if err != nil {
+				b.Fatal(err)
+			}
+			serveBenchmark(b, compiled)
+		},
+	)
+}
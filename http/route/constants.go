@@ -0,0 +1,33 @@
+package route
+
+const (
+	// AllowHeader is the header key for the Allow header
+	AllowHeader = "Allow"
+
+	// ContentLengthHeader is the header key for the Content-Length header
+	ContentLengthHeader = "Content-Length"
+
+	// AcceptEncodingHeader is the header key for the Accept-Encoding header
+	AcceptEncodingHeader = "Accept-Encoding"
+
+	// ContentEncodingHeader is the header key for the Content-Encoding header
+	ContentEncodingHeader = "Content-Encoding"
+
+	// ContentTypeHeader is the header key for the Content-Type header
+	ContentTypeHeader = "Content-Type"
+
+	// CacheControlHeader is the header key for the Cache-Control header
+	CacheControlHeader = "Cache-Control"
+
+	// ETagHeader is the header key for the ETag header
+	ETagHeader = "ETag"
+
+	// VaryHeader is the header key for the Vary header
+	VaryHeader = "Vary"
+
+	// DefaultIndexFile is the default index file of the static files directories
+	DefaultIndexFile = "index.html"
+
+	// DefaultHostWildcardKey is the wildcards context key of the subdomain captured by a '*' host pattern
+	DefaultHostWildcardKey = "subdomain"
+)
@@ -0,0 +1,81 @@
+package route
+
+import (
+	"context"
+	"net/http"
+
+	gonethttpctx "github.com/ralvarezdev/go-net/http/context"
+)
+
+// SetCtxRoute sets the matched route in the context
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//   - route: The matched route
+//
+// Returns:
+//
+//   - *http.Request: The HTTP request with the route set in the context
+func SetCtxRoute(r *http.Request, route *Route) *http.Request {
+	ctx := context.WithValue(r.Context(), gonethttpctx.CtxRouteKey, route)
+	return r.WithContext(ctx)
+}
+
+// GetCtxRoute tries to get the matched route from the context
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//
+// Returns:
+//
+//   - *Route: The route from the context, or nil if not found
+func GetCtxRoute(r *http.Request) *Route {
+	route, ok := r.Context().Value(gonethttpctx.CtxRouteKey).(*Route)
+	if !ok {
+		return nil
+	}
+	return route
+}
+
+// GetCtxRouteMetadata tries to get the metadata of the matched route from the context
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//
+// Returns:
+//
+//   - *Metadata: The route metadata from the context, or nil if not found
+func GetCtxRouteMetadata(r *http.Request) *Metadata {
+	route := GetCtxRoute(r)
+	if route == nil {
+		return nil
+	}
+	return route.Metadata
+}
+
+// setCtxRouteMiddleware sets the route of the router tree that serves the request in the context, so the router
+// middlewares can read its metadata. The route is matched again by the sub-routers if it's not found, since the
+// router middlewares can rewrite the path, e.g. to the version prefix
+//
+// Parameters:
+//
+//   - next: The next handler
+//
+// Returns:
+//
+//   - http.Handler: The middleware handler
+func (r *Router) setCtxRouteMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			if GetCtxRoute(req) == nil {
+				if route := r.match(req); route != nil {
+					req = SetCtxRoute(req, route)
+				}
+			}
+			next.ServeHTTP(w, req)
+		},
+	)
+}
@@ -4,10 +4,29 @@ import (
 	"errors"
 )
 
+var (
+	ErrCodeRouteNotFound    string
+	ErrCodeMethodNotAllowed string
+	ErrCodeHostNotFound     string
+)
+
 const (
-	ErrNilMiddleware      = "%s: middleware at index %d cannot be nil"
-	ErrNilEndpointHandler = "endpoint handler cannot be nil, pattern: %s"
-	ErrNilHandlerFunc     = "handler function cannot be nil, pattern: %s"
+	ErrNilMiddleware            = "%s: middleware at index %d cannot be nil"
+	ErrNilEndpointHandler       = "endpoint handler cannot be nil, pattern: %s"
+	ErrNilHandlerFunc           = "handler function cannot be nil, pattern: %s"
+	ErrEmptyRouteName           = "route name cannot be empty, pattern: %s"
+	ErrDuplicateRouteName       = "route name already registered: %s"
+	ErrRouteNameNotFound        = "route name not found: %s"
+	ErrMissingRouteParam        = "missing parameter '%s' for route: %s"
+	ErrUnexpectedRouteParam     = "unexpected parameter '%s' for route: %s"
+	ErrInvalidHostPattern       = "invalid host pattern: %s"
+	ErrDuplicateHost            = "host pattern already registered: %s"
+	ErrNilNamedMiddleware       = "%s: named middleware at index %d cannot be nil"
+	ErrEmptyNamedMiddlewareName = "%s: named middleware at index %d has an empty name"
+	ErrDuplicateNamedMiddleware = "%s: named middleware already registered: %s"
+	ErrNamedMiddlewaresCycle    = "named middlewares ordering hints have a cycle between: %s"
+	ErrCompileRoute             = "failed to compile route '%s': %v"
+	ErrInvalidBaseURL           = "invalid base URL '%s': %v"
 )
 
 var (
@@ -15,4 +34,9 @@ var (
 	ErrEmptyPattern      = errors.New("pattern cannot be empty")
 	ErrEmptyWildcard     = errors.New("wildcard cannot be empty")
 	ErrWildcardNotClosed = errors.New("wildcard not closed")
+	ErrRouteNotFound     = errors.New("route not found")
+	ErrMethodNotAllowed  = errors.New("method not allowed")
+	ErrHostNotFound      = errors.New("host not found")
+	ErrNilFileSystem     = errors.New("file system cannot be nil")
+	ErrNilBaseURL        = errors.New("base URL is not set")
 )
@@ -0,0 +1,130 @@
+package route
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+type (
+	// headResponseWriter is the response writer used to serve HEAD requests with GET handlers. It discards the body,
+	// and reports its length in the Content-Length header
+	headResponseWriter struct {
+		http.ResponseWriter
+		status      int
+		length      int
+		wroteHeader bool
+		returned    bool
+		finished    bool
+	}
+)
+
+// newHeadResponseWriter creates a new HEAD response writer
+//
+// Parameters:
+//
+//   - w: The HTTP response writer
+//
+// Returns:
+//
+//   - *headResponseWriter: The HEAD response writer
+func newHeadResponseWriter(w http.ResponseWriter) *headResponseWriter {
+	return &headResponseWriter{
+		ResponseWriter: w,
+	}
+}
+
+// WriteHeader records the HTTP status, it's written once the handler returns
+//
+// Parameters:
+//
+//   - status: The HTTP status
+func (h *headResponseWriter) WriteHeader(status int) {
+	if h.wroteHeader {
+		return
+	}
+	h.status = status
+	h.wroteHeader = true
+}
+
+// Write discards the body, counting its length
+//
+// Parameters:
+//
+//   - body: The body bytes
+//
+// Returns:
+//
+//   - int: The number of bytes discarded
+//   - error: Always nil
+func (h *headResponseWriter) Write(body []byte) (int, error) {
+	if !h.wroteHeader {
+		h.WriteHeader(http.StatusOK)
+	}
+	h.length += len(body)
+	return len(body), nil
+}
+
+// Flush does nothing, since the body is discarded and the headers are written once the handler returns. It's checked
+// by http.ResponseController before unwrapping the writer, so a flush can't write the headers early
+func (h *headResponseWriter) Flush() {}
+
+// Hijack hijacks the connection of the original response writer, so the headers aren't written once the handler
+// returns
+//
+// Returns:
+//
+//   - net.Conn: The hijacked connection
+//   - *bufio.ReadWriter: The buffered reader and writer of the connection
+//   - error: The error if any
+func (h *headResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, buffer, err := http.NewResponseController(h.ResponseWriter).Hijack()
+	if err == nil {
+		h.finished = true
+	}
+	return conn, buffer, err
+}
+
+// Unwrap returns the original response writer, used by http.ResponseController for the operations other than flushing
+// and hijacking, e.g. setting the write deadlines
+//
+// Returns:
+//
+//   - http.ResponseWriter: The original response writer
+func (h *headResponseWriter) Unwrap() http.ResponseWriter {
+	return h.ResponseWriter
+}
+
+// serve serves a HEAD request with a GET handler, writing the HTTP status and the Content-Length header once the
+// handler returns
+//
+// Parameters:
+//
+//   - handler: The GET handler
+//   - req: The HTTP request
+func (h *headResponseWriter) serve(handler http.Handler, req *http.Request) {
+	defer h.finish()
+	handler.ServeHTTP(h, req)
+	h.returned = true
+}
+
+// finish writes the HTTP status and the Content-Length header of the discarded body. If the handler panicked before
+// writing its status, nothing is written, so the recovery middlewares can write their own response
+func (h *headResponseWriter) finish() {
+	if h.finished || (!h.returned && !h.wroteHeader) {
+		return
+	}
+	h.finished = true
+
+	if !h.wroteHeader {
+		h.status = http.StatusOK
+	}
+
+	// Report the length of the body that a GET request would have received
+	if h.status != http.StatusNoContent && h.status != http.StatusNotModified &&
+		h.Header().Get(ContentLengthHeader) == "" {
+		h.Header().Set(ContentLengthHeader, strconv.Itoa(h.length))
+	}
+	h.ResponseWriter.WriteHeader(h.status)
+}
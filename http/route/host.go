@@ -0,0 +1,244 @@
+package route
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+
+	gonethttpctx "github.com/ralvarezdev/go-net/http/context"
+	gonethttphandler "github.com/ralvarezdev/go-net/http/handler"
+)
+
+type (
+	// wildcardHost is a router registered for the subdomains of a host
+	wildcardHost struct {
+		suffix string
+		key    string
+		router RouterWrapper
+	}
+
+	// HostDispatcher is the root handler that dispatches the requests to the router tree of their host
+	HostDispatcher struct {
+		hosts         map[string]RouterWrapper
+		wildcardHosts []*wildcardHost
+		defaultRouter RouterWrapper
+		handler       gonethttphandler.Handler
+		logger        *slog.Logger
+	}
+)
+
+// NewHostDispatcher creates a new host dispatcher
+//
+// Parameters:
+//
+//   - handler: The handler to respond to the requests of unknown hosts
+//   - logger: The logger
+//
+// Returns:
+//
+//   - *HostDispatcher: The host dispatcher
+//   - error: The error if any
+func NewHostDispatcher(
+	handler gonethttphandler.Handler,
+	logger *slog.Logger,
+) (*HostDispatcher, error) {
+	// Check if the handler is nil
+	if handler == nil {
+		return nil, gonethttphandler.ErrNilHandler
+	}
+
+	if logger != nil {
+		logger = logger.With(
+			slog.String("component", "host_dispatcher"),
+		)
+	}
+
+	return &HostDispatcher{
+		hosts:   make(map[string]RouterWrapper),
+		handler: handler,
+		logger:  logger,
+	}, nil
+}
+
+// NormalizeHost removes the port and the trailing dot of a host, and converts it to lowercase
+//
+// Parameters:
+//
+//   - host: The host, e.g. 'API.example.com:8080'
+//
+// Returns:
+//
+//   - string: The normalized host, e.g. 'api.example.com'
+func NormalizeHost(host string) string {
+	// Remove the port, also from the IPv6 hosts
+	if parsedHost, _, err := net.SplitHostPort(host); err == nil {
+		host = parsedHost
+	} else {
+		host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	}
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
+
+// AddHost registers the router tree of a host. The host pattern is matched without its port, and can be an exact
+// host, e.g. 'api.example.com', or a wildcard subdomain, e.g. '*.example.com' or '{tenant}.example.com', whose
+// captured value is set in the wildcards context with the DefaultHostWildcardKey or the given key
+//
+// Parameters:
+//
+//   - pattern: The host pattern
+//   - router: The router tree of the host
+//
+// Returns:
+//
+//   - error: The error if any
+func (d *HostDispatcher) AddHost(pattern string, router RouterWrapper) error {
+	if d == nil {
+		return ErrNilRouter
+	}
+
+	// Check if the router is nil
+	if router == nil {
+		return ErrNilRouter
+	}
+
+	// Normalize the host pattern
+	host := NormalizeHost(strings.TrimSpace(pattern))
+	if host == "" {
+		return fmt.Errorf(ErrInvalidHostPattern, pattern)
+	}
+
+	// Check if the host pattern is a wildcard subdomain
+	label, suffix, found := strings.Cut(host, ".")
+	isWildcard := label == "*" || (strings.HasPrefix(label, "{") && strings.HasSuffix(label, "}"))
+	if !isWildcard {
+		if strings.ContainsAny(host, "*{}") {
+			return fmt.Errorf(ErrInvalidHostPattern, pattern)
+		}
+		if _, ok := d.hosts[host]; ok {
+			return fmt.Errorf(ErrDuplicateHost, pattern)
+		}
+		d.hosts[host] = router
+		return nil
+	}
+
+	// Check the wildcard subdomain pattern
+	if !found || suffix == "" || strings.ContainsAny(suffix, "*{}") {
+		return fmt.Errorf(ErrInvalidHostPattern, pattern)
+	}
+	key := DefaultHostWildcardKey
+	if label != "*" {
+		key = label[1 : len(label)-1]
+		if key == "" {
+			return fmt.Errorf(ErrInvalidHostPattern, pattern)
+		}
+	}
+	for _, wildcard := range d.wildcardHosts {
+		if wildcard.suffix == "."+suffix {
+			return fmt.Errorf(ErrDuplicateHost, pattern)
+		}
+	}
+
+	// Keep the wildcard hosts sorted by the longest suffix first, so the most specific one matches
+	d.wildcardHosts = append(
+		d.wildcardHosts, &wildcardHost{
+			suffix: "." + suffix,
+			key:    key,
+			router: router,
+		},
+	)
+	sort.SliceStable(
+		d.wildcardHosts, func(i, j int) bool {
+			return len(d.wildcardHosts[i].suffix) > len(d.wildcardHosts[j].suffix)
+		},
+	)
+	return nil
+}
+
+// SetDefaultRouter sets the router tree of the requests whose host is not registered
+//
+// Parameters:
+//
+//   - router: The default router tree, if nil the unknown hosts are responded with a JSend error
+func (d *HostDispatcher) SetDefaultRouter(router RouterWrapper) {
+	if d == nil {
+		return
+	}
+	d.defaultRouter = router
+}
+
+// Handler returns the host dispatcher as an HTTP handler
+//
+// Returns:
+//
+//   - http.Handler: The HTTP handler
+func (d *HostDispatcher) Handler() http.Handler {
+	if d == nil {
+		return nil
+	}
+	return d
+}
+
+// ServeHTTP dispatches the request to the router tree of its host
+//
+// Parameters:
+//
+//   - w: The HTTP response writer
+//   - r: The HTTP request
+func (d *HostDispatcher) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if d == nil {
+		return
+	}
+
+	// Get the host of the request
+	host := r.Host
+	if host == "" && r.URL != nil {
+		host = r.URL.Host
+	}
+	host = NormalizeHost(host)
+
+	// Check the exact hosts
+	if router, ok := d.hosts[host]; ok {
+		router.Handler().ServeHTTP(w, r)
+		return
+	}
+
+	// Check the wildcard hosts, the captured subdomain must be a single label
+	for _, wildcard := range d.wildcardHosts {
+		subdomain, found := strings.CutSuffix(host, wildcard.suffix)
+		if !found || subdomain == "" || strings.Contains(subdomain, ".") {
+			continue
+		}
+
+		// Add the subdomain to the wildcards
+		wildcards := make(map[string]string)
+		for key, value := range gonethttpctx.GetCtxWildcards(r) {
+			wildcards[key] = value
+		}
+		wildcards[wildcard.key] = subdomain
+		wildcard.router.Handler().ServeHTTP(w, gonethttpctx.SetCtxWildcards(r, wildcards))
+		return
+	}
+
+	// Check the default router
+	if d.defaultRouter != nil {
+		d.defaultRouter.Handler().ServeHTTP(w, r)
+		return
+	}
+
+	if d.logger != nil {
+		d.logger.Debug(
+			"Unknown host",
+			slog.String("host", host),
+		)
+	}
+	d.handler.HandleErrorWithCode(
+		w,
+		r,
+		ErrHostNotFound,
+		ErrCodeHostNotFound,
+		http.StatusNotFound,
+	)
+}
@@ -1,8 +1,10 @@
 package route
 
 import (
+	"io/fs"
 	"log/slog"
 	"net/http"
+	"net/url"
 
 	goflagsmode "github.com/ralvarezdev/go-flags/mode"
 )
@@ -33,6 +35,18 @@ type (
 			endpointHandler func(w http.ResponseWriter, r *http.Request) error,
 			middlewares ...func(next http.Handler) http.Handler,
 		)
+		AddNamedHandleFunc(
+			name string,
+			pattern string,
+			handler http.HandlerFunc,
+			middlewares ...func(next http.Handler) http.Handler,
+		)
+		AddNamedEndpointHandler(
+			name string,
+			pattern string,
+			endpointHandler func(w http.ResponseWriter, r *http.Request) error,
+			middlewares ...func(next http.Handler) http.Handler,
+		)
 		RegisterHandler(pattern string, handler http.Handler)
 		NewRouter(
 			pattern string,
@@ -44,6 +58,22 @@ type (
 		FullPath() string
 		Method() string
 		ServeStaticFiles(pattern, path string)
+		ServeStaticFS(pattern string, fsys fs.FS, options *StaticOptions)
+		Routes() []*Route
+		Routers() []RouterWrapper
+		SetNotFoundHandler(handlerFn NotFoundHandlerFn)
+		SetMethodNotAllowedHandler(handlerFn MethodNotAllowedHandlerFn)
+		SetAutomaticOptions(enabled bool)
+		SetAutomaticHead(enabled bool)
+		SetRouteAutomaticHead(pattern string, enabled bool)
+		SetNormalizationPolicy(policy *NormalizationPolicy)
+		URL(name string, params map[string]string, query url.Values) (string, error)
+		AbsoluteURL(name string, params map[string]string, query url.Values) (string, error)
+		SetBaseURL(baseURL string) error
+		Compile() (http.Handler, error)
+		Use(middlewares ...*NamedMiddleware) error
+		Exclude(pattern string, names ...string)
+		SetRouteMetadata(pattern string, metadata *Metadata)
 		Logger() *slog.Logger
 		Mode() *goflagsmode.Flag
 	}
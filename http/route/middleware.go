@@ -25,8 +25,11 @@ func SetCtxWildcardsMiddleware(
 			func(w http.ResponseWriter, r *http.Request) {
 				// Add the wildcards to the context
 				if wildcardKeys != nil {
-					// Create a map to store the wildcards
+					// Create a map to store the wildcards, keeping the ones already set, e.g. the host wildcards
 					wildcards := make(map[string]string)
+					for key, value := range gonethttpctx.GetCtxWildcards(r) {
+						wildcards[key] = value
+					}
 
 					for _, key := range wildcardKeys {
 						// Get the wildcard from the request
@@ -0,0 +1,634 @@
+package route
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+)
+
+type (
+	// SkipPredicate is the function that checks if a named middleware must be skipped for a request
+	SkipPredicate func(r *http.Request) bool
+
+	// NamedMiddleware is a middleware that can be skipped by predicates, excluded per route by its name or groups,
+	// and ordered relative to other named middlewares
+	NamedMiddleware struct {
+		// Name is the unique name of the middleware, e.g. 'auth'
+		Name string
+
+		// Groups are the names of the groups of the middleware, e.g. 'security'
+		Groups []string
+
+		// Middleware is the middleware function
+		Middleware func(next http.Handler) http.Handler
+
+		// Skip are the predicates that skip the middleware for a request if any of them is true
+		Skip []SkipPredicate
+
+		// Before are the names of the middlewares that must run after this middleware
+		Before []string
+
+		// After are the names of the middlewares that must run before this middleware
+		After []string
+	}
+
+	// ResolvedMiddleware is a named middleware of the effective chain of a route
+	ResolvedMiddleware struct {
+		// Name is the name of the middleware
+		Name string
+
+		// Groups are the names of the groups of the middleware
+		Groups []string
+
+		// RouterPath is the full path of the router the middleware was added to
+		RouterPath string
+
+		// Inherited indicates if the middleware was added to a parent router of the route router
+		Inherited bool
+
+		// Excluded indicates if the middleware is excluded from the route by its name or by one of its groups, so
+		// it's skipped for every request of the route
+		Excluded bool
+
+		// Conditional indicates if the middleware has skip predicates, so it's skipped for the requests that match
+		// any of them
+		Conditional bool
+	}
+
+	// namedChain is the lazily resolved chain of the named middlewares of a router
+	namedChain struct {
+		router  *Router
+		next    http.Handler
+		handler http.Handler
+		empty   bool
+		once    sync.Once
+	}
+
+	// namedExclusions is the lazily resolved names and groups of the named middlewares excluded from a route
+	namedExclusions struct {
+		router  *Router
+		pattern string
+		names   []string
+		once    sync.Once
+	}
+)
+
+// NewNamedMiddleware creates a new named middleware
+//
+// Parameters:
+//
+//   - name: The name of the middleware
+//   - middleware: The middleware function
+//   - skip: The predicates that skip the middleware for a request
+//
+// Returns:
+//
+//   - *NamedMiddleware: The named middleware
+func NewNamedMiddleware(
+	name string,
+	middleware func(next http.Handler) http.Handler,
+	skip ...SkipPredicate,
+) *NamedMiddleware {
+	return &NamedMiddleware{
+		Name:       name,
+		Middleware: middleware,
+		Skip:       skip,
+	}
+}
+
+// requestPath returns the original path of the request, before the route groups stripped their prefixes
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//
+// Returns:
+//
+//   - string: The original path
+func requestPath(r *http.Request) string {
+	if r.RequestURI != "" {
+		if parsedURL, err := url.ParseRequestURI(r.RequestURI); err == nil {
+			return parsedURL.Path
+		}
+	}
+	return r.URL.Path
+}
+
+// SkipPaths returns a predicate that skips a middleware for the requests whose original path matches any of the
+// globs. The globs follow path.Match, and a trailing '/**' matches every path under the prefix
+//
+// Parameters:
+//
+//   - globs: The path globs, e.g. '/api/auth/login' or '/health/**'
+//
+// Returns:
+//
+//   - SkipPredicate: The predicate
+func SkipPaths(globs ...string) SkipPredicate {
+	return func(r *http.Request) bool {
+		requestPath := requestPath(r)
+		for _, glob := range globs {
+			if prefix, found := strings.CutSuffix(glob, "/**"); found {
+				if requestPath == prefix || strings.HasPrefix(requestPath, prefix+"/") {
+					return true
+				}
+				continue
+			}
+			if matched, err := path.Match(glob, requestPath); err == nil && matched {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// SkipMethods returns a predicate that skips a middleware for the requests with any of the methods
+//
+// Parameters:
+//
+//   - methods: The HTTP methods, e.g. 'OPTIONS'
+//
+// Returns:
+//
+//   - SkipPredicate: The predicate
+func SkipMethods(methods ...string) SkipPredicate {
+	return func(r *http.Request) bool {
+		for _, method := range methods {
+			if strings.EqualFold(r.Method, method) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// SkipHeader returns a predicate that skips a middleware for the requests with a header value
+//
+// Parameters:
+//
+//   - name: The header name
+//   - value: The header value, if empty the header only has to be present
+//
+// Returns:
+//
+//   - SkipPredicate: The predicate
+func SkipHeader(name, value string) SkipPredicate {
+	return func(r *http.Request) bool {
+		values := r.Header.Values(name)
+		if value == "" {
+			return len(values) > 0
+		}
+		for _, headerValue := range values {
+			if headerValue == value {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// matches checks if the named middleware has the name or belongs to the group
+//
+// Parameters:
+//
+//   - name: The name of the middleware or of a group
+//
+// Returns:
+//
+//   - bool: True if the named middleware matches the name, false otherwise
+func (n *NamedMiddleware) matches(name string) bool {
+	if n.Name == name {
+		return true
+	}
+	for _, group := range n.Groups {
+		if group == name {
+			return true
+		}
+	}
+	return false
+}
+
+// wrap wraps the middleware, calling the next handler directly when a skip predicate is true
+//
+// Parameters:
+//
+//   - next: The next handler
+//
+// Returns:
+//
+//   - http.Handler: The wrapped handler
+func (n *NamedMiddleware) wrap(next http.Handler) http.Handler {
+	handler := n.Middleware(next)
+	if len(n.Skip) == 0 {
+		return handler
+	}
+
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			for _, skip := range n.Skip {
+				if skip != nil && skip(r) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			handler.ServeHTTP(w, r)
+		},
+	)
+}
+
+// SortNamedMiddlewares sorts the named middlewares following their ordering hints, keeping the given order when
+// there's no hint between them. The hints referencing middlewares that are not in the slice are ignored
+//
+// Parameters:
+//
+//   - middlewares: The named middlewares
+//
+// Returns:
+//
+//   - []*NamedMiddleware: The sorted named middlewares
+//   - error: The error if the ordering hints have a cycle
+func SortNamedMiddlewares(middlewares []*NamedMiddleware) (
+	[]*NamedMiddleware,
+	error,
+) {
+	// Get the index of each middleware
+	indexes := make(map[string]int, len(middlewares))
+	for i, middleware := range middlewares {
+		indexes[middleware.Name] = i
+	}
+
+	// Build the edges from each middleware to the ones that must run after it
+	edges := make([][]int, len(middlewares))
+	inDegrees := make([]int, len(middlewares))
+	addEdge := func(from, to int) {
+		edges[from] = append(edges[from], to)
+		inDegrees[to]++
+	}
+	for i, middleware := range middlewares {
+		for _, name := range middleware.Before {
+			if j, ok := indexes[name]; ok {
+				addEdge(i, j)
+			}
+		}
+		for _, name := range middleware.After {
+			if j, ok := indexes[name]; ok {
+				addEdge(j, i)
+			}
+		}
+	}
+
+	// Pick the first middleware without pending predecessors, so the given order is kept
+	sorted := make([]*NamedMiddleware, 0, len(middlewares))
+	visited := make([]bool, len(middlewares))
+	for len(sorted) < len(middlewares) {
+		next := -1
+		for i := range middlewares {
+			if !visited[i] && inDegrees[i] == 0 {
+				next = i
+				break
+			}
+		}
+
+		// Check if there is a cycle
+		if next == -1 {
+			var names []string
+			for i, middleware := range middlewares {
+				if !visited[i] {
+					names = append(names, middleware.Name)
+				}
+			}
+			return nil, fmt.Errorf(ErrNamedMiddlewaresCycle, strings.Join(names, ", "))
+		}
+
+		visited[next] = true
+		sorted = append(sorted, middlewares[next])
+		for _, to := range edges[next] {
+			inDegrees[to]--
+		}
+	}
+	return sorted, nil
+}
+
+// allNamedMiddlewares returns the named middlewares of the router, including the ones inherited from its parent
+// routers
+//
+// Returns:
+//
+//   - []*NamedMiddleware: The named middlewares, in registration order
+func (r *Router) allNamedMiddlewares() []*NamedMiddleware {
+	if r == nil {
+		return nil
+	}
+
+	var middlewares []*NamedMiddleware
+	if r.parent != nil {
+		middlewares = r.parent.allNamedMiddlewares()
+	}
+	return append(middlewares, r.namedMiddlewares...)
+}
+
+// excludedMiddlewares returns the names or groups of the named middlewares excluded from a route
+//
+// Parameters:
+//
+//   - pattern: The pattern of the route
+//
+// Returns:
+//
+//   - []string: The excluded names or groups
+func (r *Router) excludedMiddlewares(pattern string) []string {
+	if r == nil {
+		return nil
+	}
+
+	// The exclusions of every route are inherited from the parent routers
+	var excluded []string
+	for router := r; router != nil; router = router.parent {
+		excluded = append(excluded, router.exclusions[""]...)
+	}
+	excluded = append(excluded, r.exclusions[pattern]...)
+
+	// The exact routes are registered with the '{$}' wildcard
+	if trimmedPattern, found := strings.CutSuffix(pattern, "{$}"); found {
+		excluded = append(excluded, r.exclusions[trimmedPattern]...)
+	}
+	return excluded
+}
+
+// Use adds named middlewares to every request of the router and of its sub-routers, applied right after the router
+// middlewares, so the named middlewares of a router run before the middlewares of its sub-routers. The ordering hints
+// only sort the named middlewares of the same router. They must be added before the router serves its first request
+//
+// Parameters:
+//
+//   - middlewares: The named middlewares
+//
+// Returns:
+//
+//   - error: The error if any
+func (r *Router) Use(middlewares ...*NamedMiddleware) error {
+	if r == nil {
+		return ErrNilRouter
+	}
+
+	// Check the named middlewares
+	all := r.allNamedMiddlewares()
+	for i, middleware := range middlewares {
+		if middleware == nil || middleware.Middleware == nil {
+			return fmt.Errorf(ErrNilNamedMiddleware, r.fullPath, i)
+		}
+		if middleware.Name == "" {
+			return fmt.Errorf(ErrEmptyNamedMiddlewareName, r.fullPath, i)
+		}
+		for _, registered := range all {
+			if registered.Name == middleware.Name {
+				return fmt.Errorf(
+					ErrDuplicateNamedMiddleware,
+					r.fullPath,
+					middleware.Name,
+				)
+			}
+		}
+		all = append(all, middleware)
+	}
+
+	// Check the ordering hints of the router named middlewares
+	routerMiddlewares := make([]*NamedMiddleware, 0, len(r.namedMiddlewares)+len(middlewares))
+	routerMiddlewares = append(routerMiddlewares, r.namedMiddlewares...)
+	if _, err := SortNamedMiddlewares(append(routerMiddlewares, middlewares...)); err != nil {
+		return err
+	}
+
+	r.namedMiddlewares = append(r.namedMiddlewares, middlewares...)
+	return nil
+}
+
+// Exclude excludes named middlewares from a route of the router, by their names or groups. They must be excluded
+// before the router serves its first request. The requests that don't match any route are not excluded, since the
+// exclusions are checked against the matched route
+//
+// Parameters:
+//
+//   - pattern: The pattern of the route, e.g. 'POST /login', if empty they're excluded from every route of the router
+//     and of its sub-routers
+//   - names: The names or groups of the named middlewares
+func (r *Router) Exclude(pattern string, names ...string) {
+	if r == nil {
+		return
+	}
+
+	// Normalize the pattern as it's registered in the multiplexer
+	if pattern != "" {
+		pattern = normalizePattern(pattern)
+	}
+
+	if r.exclusions == nil {
+		r.exclusions = make(map[string][]string)
+	}
+	r.exclusions[pattern] = append(r.exclusions[pattern], names...)
+}
+
+// sortedNamedMiddlewares returns the named middlewares of the router sorted following their ordering hints, without
+// the ones inherited from its parent routers
+//
+// Returns:
+//
+//   - []*NamedMiddleware: The sorted named middlewares
+func (r *Router) sortedNamedMiddlewares() []*NamedMiddleware {
+	r.namedOnce.Do(
+		func() {
+			// Sort the middlewares, keeping the registration order if a later added middleware created a cycle
+			sorted, err := SortNamedMiddlewares(r.namedMiddlewares)
+			if err != nil {
+				if r.logger != nil {
+					r.logger.Error(
+						"Failed to sort the named middlewares",
+						slog.String("full_path", r.fullPath),
+						slog.String("error", err.Error()),
+					)
+				}
+				sorted = r.namedMiddlewares
+			}
+			r.sortedNamed = sorted
+		},
+	)
+	return r.sortedNamed
+}
+
+// namedMiddleware returns the chain of the named middlewares of the router, applied after the router middlewares
+//
+// Parameters:
+//
+//   - next: The next handler
+//
+// Returns:
+//
+//   - http.Handler: The named middlewares chain
+func (r *Router) namedMiddleware(next http.Handler) http.Handler {
+	return &namedChain{
+		router: r,
+		next:   next,
+	}
+}
+
+// wrap wraps a named middleware of the chain, calling the next handler directly when it's excluded from the route in
+// the context
+//
+// Parameters:
+//
+//   - middleware: The named middleware
+//   - next: The next handler
+//
+// Returns:
+//
+//   - http.Handler: The wrapped handler
+func (c *namedChain) wrap(middleware *NamedMiddleware, next http.Handler) http.Handler {
+	handler := middleware.wrap(next)
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if route := GetCtxRoute(r); route != nil && route.excludes(middleware) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			handler.ServeHTTP(w, r)
+		},
+	)
+}
+
+// resolve chains the named middlewares of the router, in their sorted order
+func (c *namedChain) resolve() {
+	c.once.Do(
+		func() {
+			sorted := c.router.sortedNamedMiddlewares()
+			c.empty = len(sorted) == 0
+			c.handler = c.next
+			for i := len(sorted) - 1; i >= 0; i-- {
+				c.handler = c.wrap(sorted[i], c.handler)
+			}
+
+			if router := c.router; router.logger != nil && router.mode != nil && router.mode.IsDebug() && !c.empty {
+				names := make([]string, len(sorted))
+				for i, middleware := range sorted {
+					names[i] = middleware.Name
+				}
+				router.logger.Debug(
+					"Resolved router named middlewares",
+					slog.String("full_path", router.fullPath),
+					slog.Any("middlewares", names),
+				)
+			}
+		},
+	)
+}
+
+// ServeHTTP serves the request with the named middlewares chain, matching the route again if it's not set in the
+// context, e.g. when a router middleware rewrote the path to the version prefix
+//
+// Parameters:
+//
+//   - w: The HTTP response writer
+//   - r: The HTTP request
+func (c *namedChain) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.resolve()
+
+	if !c.empty && GetCtxRoute(r) == nil {
+		if route := c.router.match(r); route != nil {
+			r = SetCtxRoute(r, route)
+		}
+	}
+	c.handler.ServeHTTP(w, r)
+}
+
+// resolve resolves the names and groups of the named middlewares excluded from the route
+//
+// Returns:
+//
+//   - []string: The excluded names and groups
+func (e *namedExclusions) resolve() []string {
+	e.once.Do(
+		func() {
+			e.names = e.router.excludedMiddlewares(e.pattern)
+		},
+	)
+	return e.names
+}
+
+// excludes checks if a named middleware is excluded from the route
+//
+// Parameters:
+//
+//   - middleware: The named middleware
+//
+// Returns:
+//
+//   - bool: True if the named middleware is excluded, false otherwise
+func (r *Route) excludes(middleware *NamedMiddleware) bool {
+	if r == nil || r.exclusions == nil {
+		return false
+	}
+	for _, name := range r.exclusions.resolve() {
+		if middleware.matches(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolvedMiddlewares returns the named middlewares of the route chain, in the order they're applied, from the ones
+// of the base router to the ones of the route router. The excluded middlewares are included and marked, since they
+// stay in the chain but are skipped for the route. The plain middlewares of the routers and of the route are not
+// included, since they're not named
+//
+// Returns:
+//
+//   - []*ResolvedMiddleware: The resolved named middlewares
+func (r *Route) ResolvedMiddlewares() []*ResolvedMiddleware {
+	if r == nil || r.exclusions == nil {
+		return nil
+	}
+
+	// Get the routers from the base router to the route router
+	routeRouter := r.exclusions.router
+	var routers []*Router
+	for router := routeRouter; router != nil; router = router.parent {
+		routers = append(routers, router)
+	}
+
+	var resolved []*ResolvedMiddleware
+	for i := len(routers) - 1; i >= 0; i-- {
+		for _, middleware := range routers[i].sortedNamedMiddlewares() {
+			resolved = append(
+				resolved, &ResolvedMiddleware{
+					Name:        middleware.Name,
+					Groups:      middleware.Groups,
+					RouterPath:  routers[i].fullPath,
+					Inherited:   routers[i] != routeRouter,
+					Excluded:    r.excludes(middleware),
+					Conditional: len(middleware.Skip) > 0,
+				},
+			)
+		}
+	}
+	return resolved
+}
+
+// NamedMiddlewares returns the names of the named middlewares applied to the route, in the order they're applied,
+// without the excluded ones. The skip predicates are evaluated per request, so the conditional middlewares are
+// included. See ResolvedMiddlewares to get the whole chain
+//
+// Returns:
+//
+//   - []string: The names of the named middlewares
+func (r *Route) NamedMiddlewares() []string {
+	var names []string
+	for _, middleware := range r.ResolvedMiddlewares() {
+		if !middleware.Excluded {
+			names = append(names, middleware.Name)
+		}
+	}
+	return names
+}
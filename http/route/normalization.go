@@ -0,0 +1,298 @@
+package route
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+type (
+	// NormalizationAction is the action taken when a request path is not normalized
+	NormalizationAction int
+
+	// NormalizationPolicy is the policy to normalize the request paths before routing them
+	NormalizationPolicy struct {
+		// TrailingSlash adds or removes the trailing slash of the paths that don't match any route, when the path
+		// with the other form does, e.g. '/users/' to '/users'
+		TrailingSlash NormalizationAction
+
+		// CollapseSlashes replaces the duplicate slashes with a single one, e.g. '/users//1' to '/users/1'. If set to
+		// NormalizationNone, http.ServeMux still redirects the paths with duplicate slashes with a 307 status
+		CollapseSlashes NormalizationAction
+
+		// CleanDotSegments resolves the '.' and '..' segments, e.g. '/users/./1' to '/users/1'. If set to
+		// NormalizationNone, http.ServeMux still redirects the paths with dot segments with a 307 status
+		CleanDotSegments NormalizationAction
+
+		// Lowercase converts the paths to lowercase, including their wildcards values
+		Lowercase NormalizationAction
+	}
+)
+
+const (
+	// NormalizationNone keeps the path as it is. The multiplexer always cleans the paths before matching them, so the
+	// paths with duplicate slashes or dot segments are still redirected to their cleaned path by http.ServeMux, with
+	// a 307 status, since the router can't route them unchanged
+	NormalizationNone NormalizationAction = iota
+
+	// NormalizationRedirect redirects the request to the normalized path, with a 301 status for the GET and HEAD
+	// requests and a 308 status for the other methods, so they keep their method and body
+	NormalizationRedirect
+
+	// NormalizationRewrite routes the request with the normalized path, without redirecting it
+	NormalizationRewrite
+)
+
+// apply applies a normalization action
+//
+// Parameters:
+//
+//   - action: The normalization action
+//   - path: The current path
+//   - normalizedPath: The path with the normalization applied
+//   - redirect: Whether the request must be redirected, set to true if the action is a redirect that changed the path
+//
+// Returns:
+//
+//   - string: The path to keep normalizing
+func (p *NormalizationPolicy) apply(
+	action NormalizationAction,
+	path, normalizedPath string,
+	redirect *bool,
+) string {
+	if action == NormalizationNone || path == normalizedPath {
+		return path
+	}
+	if action == NormalizationRedirect {
+		*redirect = true
+	}
+	return normalizedPath
+}
+
+// collapseSlashes replaces the duplicate slashes of a path with a single one
+//
+// Parameters:
+//
+//   - path: The path
+//
+// Returns:
+//
+//   - string: The path without duplicate slashes
+func collapseSlashes(path string) string {
+	if !strings.Contains(path, "//") {
+		return path
+	}
+
+	var builder strings.Builder
+	builder.Grow(len(path))
+	for i := 0; i < len(path); i++ {
+		if path[i] == '/' && i > 0 && path[i-1] == '/' {
+			continue
+		}
+		builder.WriteByte(path[i])
+	}
+	return builder.String()
+}
+
+// cleanDotSegments resolves the dot segments of a path, keeping its trailing slash
+//
+// Parameters:
+//
+//   - urlPath: The path
+//
+// Returns:
+//
+//   - string: The path without dot segments
+func cleanDotSegments(urlPath string) string {
+	if !strings.Contains(urlPath, ".") {
+		return urlPath
+	}
+
+	cleanedPath := path.Clean(urlPath)
+	if cleanedPath != "/" && (strings.HasSuffix(urlPath, "/") ||
+		strings.HasSuffix(urlPath, "/.") || strings.HasSuffix(urlPath, "/..")) {
+		cleanedPath += "/"
+	}
+	return cleanedPath
+}
+
+// toggleTrailingSlash adds or removes the trailing slash of a path
+//
+// Parameters:
+//
+//   - path: The path
+//
+// Returns:
+//
+//   - string: The path with the other trailing slash form, or the same path if it's the root path
+func toggleTrailingSlash(path string) string {
+	switch {
+	case path == "/" || path == "":
+		return path
+	case strings.HasSuffix(path, "/"):
+		return path[:len(path)-1]
+	default:
+		return path + "/"
+	}
+}
+
+// SetNormalizationPolicy sets the policy to normalize the request paths before routing them. It's intended for the
+// base router, since the sub-routers only see the path relative to them. The sub-routers with a policy are not
+// flattened by Compile
+//
+// Parameters:
+//
+//   - policy: The normalization policy, if nil the paths are routed as they are
+func (r *Router) SetNormalizationPolicy(policy *NormalizationPolicy) {
+	if r == nil {
+		return
+	}
+	r.normalization = policy
+}
+
+// matches checks if a path matches a route of the router tree, following the route groups. The trailing slash
+// redirects of the multiplexer are not considered matches
+//
+// Parameters:
+//
+//   - req: The HTTP request
+//   - urlPath: The path relative to the router
+//
+// Returns:
+//
+//   - bool: True if the path matches a route, false otherwise
+func (r *Router) matches(req *http.Request, urlPath string) bool {
+	if r == nil {
+		return false
+	}
+
+	// Probe the multiplexer with the path
+	probeURL := *req.URL
+	probeURL.Path = urlPath
+	probeURL.RawPath = ""
+	probe := *req
+	probe.URL = &probeURL
+	_, pattern := r.mux.Handler(&probe)
+	if pattern == "" {
+		return false
+	}
+
+	// Get the matched route
+	matchedRoute := r.patterns[pattern]
+	if matchedRoute == nil {
+		return true
+	}
+
+	// Check if the multiplexer would redirect the path to the route group path with trailing slash
+	if matchedRoute.mount && matchedRoute.Path == urlPath+"/" {
+		return false
+	}
+
+	// Follow the route group, with the path relative to it
+	if subRouter, ok := matchedRoute.router.(*Router); ok {
+		prefix := strings.TrimSuffix(matchedRoute.Path, "/")
+		return subRouter.matches(req, strings.TrimPrefix(urlPath, prefix))
+	}
+	return true
+}
+
+// normalizePath normalizes a path following the normalization policy
+//
+// Parameters:
+//
+//   - req: The HTTP request
+//   - urlPath: The path relative to the router
+//
+// Returns:
+//
+//   - string: The normalized path
+//   - bool: True if the request must be redirected to the normalized path, false otherwise
+func (r *Router) normalizePath(req *http.Request, urlPath string) (string, bool) {
+	policy := r.normalization
+	if policy == nil {
+		return urlPath, false
+	}
+
+	// Normalize the path
+	redirect := false
+	normalizedPath := policy.apply(
+		policy.CollapseSlashes,
+		urlPath,
+		collapseSlashes(urlPath),
+		&redirect,
+	)
+	normalizedPath = policy.apply(
+		policy.CleanDotSegments,
+		normalizedPath,
+		cleanDotSegments(normalizedPath),
+		&redirect,
+	)
+	normalizedPath = policy.apply(
+		policy.Lowercase,
+		normalizedPath,
+		strings.ToLower(normalizedPath),
+		&redirect,
+	)
+
+	// Toggle the trailing slash if only the other form matches a route
+	if policy.TrailingSlash != NormalizationNone && !r.matches(req, normalizedPath) {
+		if toggledPath := toggleTrailingSlash(normalizedPath); r.matches(req, toggledPath) {
+			normalizedPath = policy.apply(
+				policy.TrailingSlash,
+				normalizedPath,
+				toggledPath,
+				&redirect,
+			)
+		}
+	}
+	return normalizedPath, redirect
+}
+
+// normalize normalizes the request path following the normalization policy
+//
+// Parameters:
+//
+//   - w: The HTTP response writer
+//   - req: The HTTP request
+//
+// Returns:
+//
+//   - *http.Request: The request to route, with the rewritten path if it was normalized
+//   - bool: True if the request was redirected, false otherwise
+func (r *Router) normalize(w http.ResponseWriter, req *http.Request) (*http.Request, bool) {
+	if r.normalization == nil {
+		return req, false
+	}
+
+	// Check if the path is already normalized
+	urlPath := req.URL.Path
+	normalizedPath, redirect := r.normalizePath(req, urlPath)
+	if normalizedPath == urlPath {
+		return req, false
+	}
+
+	// Redirect the request to the normalized path, adding the prefix stripped by the parent routers and keeping the
+	// query. The location is built from the routed path, since the middlewares could have rewritten it, e.g. to the
+	// version prefix of a versioned module
+	if redirect {
+		locationURL := url.URL{
+			Path:     strings.TrimSuffix(r.fullPath, "/") + normalizedPath,
+			RawQuery: req.URL.RawQuery,
+		}
+		location := locationURL.String()
+
+		status := http.StatusPermanentRedirect
+		if req.Method == http.MethodGet || req.Method == http.MethodHead {
+			status = http.StatusMovedPermanently
+		}
+		http.Redirect(w, req, location, status)
+		return nil, true
+	}
+
+	// Rewrite the path
+	rewrittenReq := req.Clone(req.Context())
+	rewrittenReq.URL.Path = normalizedPath
+	rewrittenReq.URL.RawPath = ""
+	return rewrittenReq, false
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"sort"
+	"strings"
+	"sync"
 
 	goflagsmode "github.com/ralvarezdev/go-flags/mode"
 
@@ -23,6 +26,24 @@ type (
 		handler      gonethttphandler.Handler
 		mode         *goflagsmode.Flag
 		logger       *slog.Logger
+		routes       []*Route
+		patterns     map[string]*Route
+		routers      []RouterWrapper
+		names        *routeNames
+		parent       *Router
+
+		namedMiddlewares []*NamedMiddleware
+		sortedNamed      []*NamedMiddleware
+		namedOnce        sync.Once
+		exclusions       map[string][]string
+		metadata         map[string]*Metadata
+		automaticHead    map[string]bool
+
+		notFoundHandlerFn         NotFoundHandlerFn
+		methodNotAllowedHandlerFn MethodNotAllowedHandlerFn
+		disableAutomaticOptions   bool
+		disableAutomaticHead      bool
+		normalization             *NormalizationPolicy
 	}
 )
 
@@ -67,9 +88,6 @@ func NewRouter(
 		}
 	}
 
-	// Chain the handlers
-	firstHandler := ChainHandlers(mux, middlewares...)
-
 	if logger != nil {
 		logger = logger.With(
 			slog.String("component", "router"),
@@ -77,18 +95,22 @@ func NewRouter(
 		)
 	}
 
-	return &Router{
-		middlewares,
-		firstHandler,
-		mux,
-		pattern,
-		path,
-		path,
-		method,
-		handler,
-		mode,
-		logger,
-	}, nil
+	instance := &Router{
+		middlewares:  middlewares,
+		mux:          mux,
+		pattern:      pattern,
+		relativePath: path,
+		fullPath:     path,
+		method:       method,
+		handler:      handler,
+		mode:         mode,
+		logger:       logger,
+		names:        newRouteNames(),
+	}
+
+	// Chain the handlers, setting the route in the context before the middlewares
+	instance.firstHandler = instance.setCtxRouteMiddleware(instance.chainRouterMiddlewares(middlewares))
+	return instance, nil
 }
 
 // NewBaseRouter creates a new base router
@@ -137,6 +159,19 @@ func (r *Router) Mux() *http.ServeMux {
 	return r.mux
 }
 
+// chainRouterMiddlewares chains the router middlewares and its named middlewares to the router multiplexer
+//
+// Parameters:
+//
+//   - middlewares: The router middlewares
+//
+// Returns:
+//
+//   - http.Handler: The chained handler
+func (r *Router) chainRouterMiddlewares(middlewares []func(http.Handler) http.Handler) http.Handler {
+	return ChainHandlers(r.namedMiddleware(http.HandlerFunc(r.serveHTTP)), middlewares...)
+}
+
 // GetMiddlewares returns the middlewares
 //
 // Returns:
@@ -200,6 +235,10 @@ func (r *Router) chainMiddlewares(
 	// Chain the handlers
 	firstHandler := ChainHandlers(handler, middlewares...)
 
+	// Check if the route matches any method
+	if method == "" {
+		return parsedPath, firstHandler
+	}
 	return method + " " + parsedPath, firstHandler
 }
 
@@ -207,11 +246,13 @@ func (r *Router) chainMiddlewares(
 //
 // Parameters:
 //
+//   - name: The name of the route, empty if the route is unnamed
 //   - pattern: The pattern of the route
 //   - handler: The handler function
 //   - exact: Whether the route should match the exact path
 //   - middlewares: The middlewares to apply to the route
 func (r *Router) addHandleFunc(
+	name string,
 	pattern string,
 	handler http.HandlerFunc,
 	exact bool,
@@ -226,6 +267,11 @@ func (r *Router) addHandleFunc(
 		panic(fmt.Sprintf(ErrNilHandlerFunc, pattern))
 	}
 
+	// Check if the route name is already registered, before registering the route
+	if name != "" && r.names.has(name) {
+		panic(fmt.Sprintf(ErrDuplicateRouteName, name))
+	}
+
 	// Chain the middlewares
 	pattern, firstHandler := r.chainMiddlewares(
 		pattern,
@@ -236,6 +282,16 @@ func (r *Router) addHandleFunc(
 
 	// Register the route
 	r.mux.HandleFunc(pattern, firstHandler.ServeHTTP)
+	route := r.addRoute(pattern, firstHandler, false)
+	route.exclusions = &namedExclusions{router: r, pattern: pattern}
+	route.Metadata = r.routeMetadata(pattern)
+	route.disableAutomaticHead = !r.routeAutomaticHead(pattern)
+
+	// Register the route name
+	if name != "" {
+		route.Name = name
+		r.names.add(route)
+	}
 
 	if r.mode != nil && r.mode.IsDebug() {
 		AddRouter(r.fullPath, pattern, r.logger)
@@ -259,7 +315,7 @@ func (r *Router) AddHandleFunc(
 	}
 
 	// Add the route
-	r.addHandleFunc(pattern, handler, false, middlewares...)
+	r.addHandleFunc("", pattern, handler, false, middlewares...)
 }
 
 // AddExactHandleFunc registers a new route with a path, the handler function and the middlewares
@@ -279,7 +335,62 @@ func (r *Router) AddExactHandleFunc(
 	}
 
 	// Add the route
-	r.addHandleFunc(pattern, handler, true, middlewares...)
+	r.addHandleFunc("", pattern, handler, true, middlewares...)
+}
+
+// AddNamedHandleFunc registers a new named route with a path, the handler function and the middlewares. The name is
+// used to build the route URL with the URL method
+//
+// Parameters:
+//
+//   - name: The name of the route, unique across the router and its sub-routers
+//   - pattern: The pattern of the route
+//   - handler: The handler function
+//   - middlewares: The middlewares to apply to the route
+func (r *Router) AddNamedHandleFunc(
+	name string,
+	pattern string,
+	handler http.HandlerFunc,
+	middlewares ...func(http.Handler) http.Handler,
+) {
+	if r == nil {
+		return
+	}
+
+	// Check if the name is empty
+	if name == "" {
+		panic(fmt.Sprintf(ErrEmptyRouteName, pattern))
+	}
+
+	// Add the route
+	r.addHandleFunc(name, pattern, handler, false, middlewares...)
+}
+
+// wrapEndpointHandler wraps an endpoint handler, handling its returned error
+//
+// Parameters:
+//
+//   - pattern: The pattern of the endpoint
+//   - handler: The endpoint handler
+//
+// Returns:
+//
+//   - http.HandlerFunc: The wrapped handler
+func (r *Router) wrapEndpointHandler(
+	pattern string,
+	handler func(w http.ResponseWriter, r *http.Request) error,
+) http.HandlerFunc {
+	// Check if the handler is nil
+	if handler == nil {
+		panic(fmt.Sprintf(ErrNilEndpointHandler, pattern))
+	}
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		if err := handler(w, req); err != nil {
+			// Handle the error using the handler's HandleRawError method
+			r.handler.HandleRawError(w, req, err, nil)
+		}
+	}
 }
 
 // AddEndpointHandler adds a new endpoint with a path, the handler function and the middlewares
@@ -298,23 +409,37 @@ func (r *Router) AddEndpointHandler(
 		return
 	}
 
-	// Check if the handler is nil
-	if handler == nil {
-		panic(fmt.Sprintf(ErrNilEndpointHandler, pattern))
+	// Wrap the endpoint handler
+	wrappedHandler := r.wrapEndpointHandler(pattern, handler)
+
+	// Add the endpoint handler
+	r.AddHandleFunc(pattern, wrappedHandler, middlewares...)
+}
+
+// AddNamedEndpointHandler adds a new named endpoint with a path, the handler function and the middlewares. The name
+// is used to build the endpoint URL with the URL method
+//
+// Parameters:
+//
+//   - name: The name of the endpoint, unique across the router and its sub-routers
+//   - pattern: The pattern of the endpoint
+//   - handler: The handler function
+//   - middlewares: The middlewares to apply to the endpoint
+func (r *Router) AddNamedEndpointHandler(
+	name string,
+	pattern string,
+	handler func(w http.ResponseWriter, r *http.Request) error,
+	middlewares ...func(http.Handler) http.Handler,
+) {
+	if r == nil {
+		return
 	}
 
 	// Wrap the endpoint handler
-	wrappedHandler := http.HandlerFunc(
-		func(w http.ResponseWriter, req *http.Request) {
-			if err := handler(w, req); err != nil {
-				// Handle the error using the handler's HandleRawError method
-				r.handler.HandleRawError(w, req, err, nil)
-			}
-		},
-	)
+	wrappedHandler := r.wrapEndpointHandler(pattern, handler)
 
 	// Add the endpoint handler
-	r.AddHandleFunc(pattern, wrappedHandler, middlewares...)
+	r.AddNamedHandleFunc(name, pattern, wrappedHandler, middlewares...)
 }
 
 // AddExactEndpointHandler adds a new endpoint with a path, the handler function and the middlewares
@@ -333,20 +458,8 @@ func (r *Router) AddExactEndpointHandler(
 		return
 	}
 
-	// Check if the handler is nil
-	if handler == nil {
-		panic(fmt.Sprintf(ErrNilEndpointHandler, pattern))
-	}
-
 	// Wrap the endpoint handler
-	wrappedHandler := http.HandlerFunc(
-		func(w http.ResponseWriter, req *http.Request) {
-			if err := handler(w, req); err != nil {
-				// Handle the error using the handler's HandleRawError method
-				r.handler.HandleRawError(w, req, err, nil)
-			}
-		},
-	)
+	wrappedHandler := r.wrapEndpointHandler(pattern, handler)
 
 	// Add the endpoint handler
 	r.AddExactHandleFunc(pattern, wrappedHandler, middlewares...)
@@ -369,7 +482,9 @@ func (r *Router) RegisterHandler(pattern string, handler http.Handler) {
 	}
 
 	// Register the route group
-	r.mux.Handle(pattern+"/", http.StripPrefix(pattern, handler))
+	strippedHandler := http.StripPrefix(pattern, handler)
+	r.mux.Handle(pattern+"/", strippedHandler)
+	r.addRoute(pattern+"/", strippedHandler, true)
 
 	if r.logger != nil && r.mode != nil && r.mode.IsDebug() {
 		r.logger.Debug(
@@ -406,16 +521,8 @@ func (r *Router) NewRouter(
 		return nil, err
 	}
 
-	// Check the base router path
-	var fullPath string
-	switch {
-	case relativePath == "/" && r.FullPath() == "/":
-		fullPath = "/"
-	case r.FullPath()[len(r.FullPath())-1] == '/':
-		fullPath = r.FullPath() + relativePath[1:]
-	default:
-		fullPath = r.FullPath() + relativePath
-	}
+	// Join the base router path with the relative path
+	fullPath := JoinPaths(r.FullPath(), relativePath)
 
 	// Initialize the multiplexer
 	mux := http.NewServeMux()
@@ -427,23 +534,29 @@ func (r *Router) NewRouter(
 		}
 	}
 
-	// Chain the handlers
-	firstHandler := ChainHandlers(mux, middlewares...)
-
-	// Create a new router
+	// Create a new router, linked to its parent to resolve the named middlewares exclusions, and inheriting the route
+	// names registry, the unmatched requests and automatic methods settings
 	instance := &Router{
-		middlewares:  middlewares,
-		firstHandler: firstHandler,
-		mux:          mux,
-		logger:       r.Logger(),
-		pattern:      pattern,
-		relativePath: relativePath,
-		fullPath:     fullPath,
-		method:       method,
-		mode:         r.Mode(),
-		handler:      r.handler,
+		middlewares:               middlewares,
+		mux:                       mux,
+		logger:                    r.Logger(),
+		pattern:                   pattern,
+		relativePath:              relativePath,
+		fullPath:                  fullPath,
+		method:                    method,
+		mode:                      r.Mode(),
+		handler:                   r.handler,
+		names:                     r.names,
+		parent:                    r,
+		notFoundHandlerFn:         r.notFoundHandlerFn,
+		methodNotAllowedHandlerFn: r.methodNotAllowedHandlerFn,
+		disableAutomaticOptions:   r.disableAutomaticOptions,
+		disableAutomaticHead:      r.disableAutomaticHead,
 	}
 
+	// Chain the handlers, setting the route in the context before the middlewares
+	instance.firstHandler = instance.setCtxRouteMiddleware(instance.chainRouterMiddlewares(middlewares))
+
 	// Add the new router to the parent router
 	r.AddRouter(instance)
 	return instance, nil
@@ -459,6 +572,10 @@ func (r *Router) AddRouter(router RouterWrapper) {
 		return
 	}
 	r.RegisterHandler(router.Pattern(), router.Handler())
+	r.routers = append(r.routers, router)
+
+	// Link the route group to the router, so it can be flattened by Compile
+	r.routes[len(r.routes)-1].router = router
 }
 
 // Pattern returns the pattern
@@ -509,52 +626,419 @@ func (r *Router) Method() string {
 	return r.method
 }
 
-// ServeStaticFiles serves the static files
+// Logger returns the logger
+//
+// Returns:
+//
+//   - *slog.Logger: The logger
+func (r *Router) Logger() *slog.Logger {
+	if r == nil {
+		return nil
+	}
+	return r.logger
+}
+
+// Mode returns the mode
+//
+// Returns:
+//
+//   - *goflagsmode.Flag: The mode
+func (r *Router) Mode() *goflagsmode.Flag {
+	if r == nil {
+		return nil
+	}
+	return r.mode
+}
+
+// addRoute adds a registered pattern to the routes registry
 //
 // Parameters:
 //
-//   - pattern: The pattern to serve the static files
-//   - path: The path to the static files
-func (r *Router) ServeStaticFiles(
-	pattern,
-	path string,
-) {
+//   - pattern: The pattern registered in the multiplexer
+//   - handler: The handler registered in the multiplexer
+//   - mount: Whether the pattern is a route group or static files prefix, served with its prefix stripped
+//
+// Returns:
+//
+//   - *Route: The added route
+func (r *Router) addRoute(pattern string, handler http.Handler, mount bool) *Route {
+	if r == nil {
+		return nil
+	}
+
+	// Split the method and path from the pattern, patterns without method match any method
+	method, path, _ := strings.Cut(pattern, " ")
+	if path == "" {
+		method, path = "", pattern
+	}
+
+	route := &Route{
+		Method:   method,
+		Path:     path,
+		Pattern:  pattern,
+		FullPath: joinRoutePath(r.fullPath, path),
+		handler:  handler,
+		mount:    mount,
+	}
+	r.indexRoute(pattern, route)
+	return route
+}
+
+// indexRoute adds a route to the routes registry, indexed by the pattern it's registered with in the multiplexer
+//
+// Parameters:
+//
+//   - pattern: The pattern registered in the multiplexer
+//   - route: The route
+func (r *Router) indexRoute(pattern string, route *Route) {
+	if r.patterns == nil {
+		r.patterns = make(map[string]*Route)
+	}
+	r.patterns[pattern] = route
+	r.routes = append(r.routes, route)
+}
+
+// match returns the route of the router tree that serves a request, following the route groups
+//
+// Parameters:
+//
+//   - req: The HTTP request
+//
+// Returns:
+//
+//   - *Route: The matched route, nil if the request doesn't match any route
+func (r *Router) match(req *http.Request) *Route {
+	// Probe the multiplexers with a copy of the request, whose path is relative to each router
+	probeURL := *req.URL
+	probeURL.RawPath = ""
+	probe := *req
+	probe.URL = &probeURL
+	for router := r; router != nil; {
+		probeURL.Path, _ = router.normalizePath(req, probeURL.Path)
+		_, pattern := router.mux.Handler(&probe)
+		route := router.patterns[pattern]
+		if route == nil {
+			return nil
+		}
+
+		// Follow the route group, with the path relative to it
+		subRouter, ok := route.router.(*Router)
+		if !ok {
+			return route
+		}
+		probeURL.Path = strings.TrimPrefix(probeURL.Path, strings.TrimSuffix(route.Path, "/"))
+		router = subRouter
+	}
+	return nil
+}
+
+// Routes returns the routes registered in the router, without the ones of its sub-routers
+//
+// Returns:
+//
+//   - []*Route: The routes
+func (r *Router) Routes() []*Route {
+	if r == nil {
+		return nil
+	}
+	return r.routes
+}
+
+// Routers returns the sub-routers registered in the router
+//
+// Returns:
+//
+//   - []RouterWrapper: The sub-routers
+func (r *Router) Routers() []RouterWrapper {
+	if r == nil {
+		return nil
+	}
+	return r.routers
+}
+
+// SetNotFoundHandler sets the handler for the requests that don't match any route of the router. The sub-routers
+// created afterward inherit it
+//
+// Parameters:
+//
+//   - handlerFn: The handler function, if nil the router handler responds with a JSend error
+func (r *Router) SetNotFoundHandler(handlerFn NotFoundHandlerFn) {
 	if r == nil {
 		return
 	}
+	r.notFoundHandlerFn = handlerFn
+}
 
-	// Check if the pattern contains a trailing slash and add it
-	if pattern == "" || pattern[len(pattern)-1] != '/' {
-		pattern += "/"
+// SetMethodNotAllowedHandler sets the handler for the requests whose path matches a route of the router, but not its
+// method. The sub-routers created afterward inherit it
+//
+// Parameters:
+//
+//   - handlerFn: The handler function, if nil the router handler responds with a JSend error
+func (r *Router) SetMethodNotAllowedHandler(handlerFn MethodNotAllowedHandlerFn) {
+	if r == nil {
+		return
 	}
+	r.methodNotAllowedHandlerFn = handlerFn
+}
 
-	// Serve the static files
-	r.mux.HandleFunc(
-		pattern,
-		http.StripPrefix(pattern, http.FileServer(http.Dir(path))).ServeHTTP,
+// allowedMethods returns the methods of the registered routes that match the request path
+//
+// Parameters:
+//
+//   - req: The HTTP request
+//
+// Returns:
+//
+//   - []string: The sorted allowed methods, including HEAD if GET is allowed
+func (r *Router) allowedMethods(req *http.Request) []string {
+	if r == nil {
+		return nil
+	}
+
+	// Probe the multiplexer with every registered method
+	probe := req.WithContext(req.Context())
+	allowed := make(map[string]struct{})
+	for _, route := range r.routes {
+		if route.Method == "" {
+			continue
+		}
+		if _, ok := allowed[route.Method]; ok {
+			continue
+		}
+
+		probe.Method = route.Method
+		if _, pattern := r.mux.Handler(probe); pattern != "" {
+			allowed[route.Method] = struct{}{}
+		}
+	}
+
+	// A GET route also serves HEAD requests
+	if _, ok := allowed[http.MethodGet]; ok {
+		allowed[http.MethodHead] = struct{}{}
+	}
+
+	// The automatic OPTIONS response is available for every matched path
+	if len(allowed) > 0 && !r.disableAutomaticOptions {
+		allowed[http.MethodOptions] = struct{}{}
+	}
+
+	methods := make([]string, 0, len(allowed))
+	for method := range allowed {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// serveHTTP serves the request with the multiplexer, responding to the unmatched requests through the router handler
+//
+// Parameters:
+//
+//   - w: The HTTP response writer
+//   - req: The HTTP request
+func (r *Router) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	// Normalize the request path
+	req, redirected := r.normalize(w, req)
+	if redirected {
+		return
+	}
+
+	// Check if the request matches a route
+	if _, pattern := r.mux.Handler(req); pattern != "" {
+		// Serve the HEAD requests matched by a GET route without body, unless a HEAD route overrides it or the route
+		// disables it
+		if req.Method == http.MethodHead && !r.disableAutomaticHead && strings.HasPrefix(
+			pattern,
+			http.MethodGet+" ",
+		) {
+			if route := r.patterns[pattern]; route == nil || !route.disableAutomaticHead {
+				newHeadResponseWriter(w).serve(r.mux, req)
+				return
+			}
+		}
+
+		r.mux.ServeHTTP(w, req)
+		return
+	}
+
+	// Check if the path matches a route with another method
+	if allowedMethods := r.allowedMethods(req); len(allowedMethods) > 0 {
+		w.Header().Set(AllowHeader, strings.Join(allowedMethods, ", "))
+
+		// Respond to the OPTIONS requests not matched by an OPTIONS route with the allowed methods
+		if req.Method == http.MethodOptions && !r.disableAutomaticOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if r.methodNotAllowedHandlerFn != nil {
+			r.methodNotAllowedHandlerFn(w, req, allowedMethods)
+			return
+		}
+		r.handler.HandleErrorWithCode(
+			w,
+			req,
+			ErrMethodNotAllowed,
+			ErrCodeMethodNotAllowed,
+			http.StatusMethodNotAllowed,
+		)
+		return
+	}
+
+	r.notFound(w, req)
+}
+
+// notFound responds to a request that doesn't match any route
+//
+// Parameters:
+//
+//   - w: The HTTP response writer
+//   - req: The HTTP request
+func (r *Router) notFound(w http.ResponseWriter, req *http.Request) {
+	if r == nil {
+		return
+	}
+
+	if r.notFoundHandlerFn != nil {
+		r.notFoundHandlerFn(w, req)
+		return
+	}
+	r.handler.HandleErrorWithCode(
+		w,
+		req,
+		ErrRouteNotFound,
+		ErrCodeRouteNotFound,
+		http.StatusNotFound,
 	)
 }
 
-// Logger returns the logger
+// SetAutomaticOptions sets whether the router answers the OPTIONS requests with the allowed methods of the matched
+// path. A route registered with the OPTIONS method overrides it. The sub-routers created afterward inherit it
+//
+// Parameters:
+//
+//   - enabled: Whether the automatic OPTIONS responses are enabled, they're enabled by default
+func (r *Router) SetAutomaticOptions(enabled bool) {
+	if r == nil {
+		return
+	}
+	r.disableAutomaticOptions = !enabled
+}
+
+// SetAutomaticHead sets whether the router serves the HEAD requests with the matched GET route, discarding the body
+// and reporting its Content-Length. A route registered with the HEAD method overrides it, and SetRouteAutomaticHead
+// disables it for a single route. The sub-routers created afterward inherit it
+//
+// Parameters:
+//
+//   - enabled: Whether the automatic HEAD responses are enabled, they're enabled by default
+func (r *Router) SetAutomaticHead(enabled bool) {
+	if r == nil {
+		return
+	}
+	r.disableAutomaticHead = !enabled
+}
+
+// SetRouteAutomaticHead sets whether the router serves the HEAD requests matched by a GET route of the router,
+// discarding the body and reporting its Content-Length. If disabled, the GET route handler serves the HEAD requests as
+// they are, and net/http discards their body. It can be set before or after registering the route, and it has no
+// effect if the automatic HEAD responses are disabled for the whole router
+//
+// Parameters:
+//
+//   - pattern: The pattern of the GET route, e.g. 'GET /users/{id}'
+//   - enabled: Whether the automatic HEAD responses are enabled for the route, they're enabled by default
+func (r *Router) SetRouteAutomaticHead(pattern string, enabled bool) {
+	if r == nil {
+		return
+	}
+
+	// Normalize the pattern as it's registered in the multiplexer
+	pattern = normalizePattern(pattern)
+	if r.automaticHead == nil {
+		r.automaticHead = make(map[string]bool)
+	}
+	r.automaticHead[pattern] = enabled
+
+	// Set it for the already registered routes
+	for _, route := range r.routes {
+		if !route.mount && (route.Pattern == pattern || route.Pattern == pattern+"{$}") {
+			route.disableAutomaticHead = !enabled
+		}
+	}
+}
+
+// routeAutomaticHead returns whether the automatic HEAD responses are enabled for a route pattern
+//
+// Parameters:
+//
+//   - pattern: The pattern registered in the multiplexer
 //
 // Returns:
 //
-//   - *slog.Logger: The logger
-func (r *Router) Logger() *slog.Logger {
+//   - bool: True if they're enabled or not set, false otherwise
+func (r *Router) routeAutomaticHead(pattern string) bool {
 	if r == nil {
-		return nil
+		return true
 	}
-	return r.logger
+
+	if enabled, ok := r.automaticHead[pattern]; ok {
+		return enabled
+	}
+
+	// The exact routes are registered with the '{$}' wildcard
+	if enabled, ok := r.automaticHead[strings.TrimSuffix(pattern, "{$}")]; ok {
+		return enabled
+	}
+	return true
 }
 
-// Mode returns the mode
+// routeMetadata returns the metadata set for a route pattern
+//
+// Parameters:
+//
+//   - pattern: The pattern registered in the multiplexer
 //
 // Returns:
 //
-//   - *goflagsmode.Flag: The mode
-func (r *Router) Mode() *goflagsmode.Flag {
+//   - *Metadata: The metadata, nil if it's not set
+func (r *Router) routeMetadata(pattern string) *Metadata {
 	if r == nil {
 		return nil
 	}
-	return r.mode
+
+	if metadata, ok := r.metadata[pattern]; ok {
+		return metadata
+	}
+
+	// The exact routes are registered with the '{$}' wildcard
+	return r.metadata[strings.TrimSuffix(pattern, "{$}")]
+}
+
+// SetRouteMetadata sets the metadata of a route of the router, it can be set before or after registering the route.
+// The metadata is readable from the request context with GetCtxRouteMetadata by the middlewares of the route and of
+// the routers that serve it, since the route is matched before the router middlewares run
+//
+// Parameters:
+//
+//   - pattern: The pattern of the route, e.g. 'GET /users/{id}'
+//   - metadata: The metadata of the route
+func (r *Router) SetRouteMetadata(pattern string, metadata *Metadata) {
+	if r == nil {
+		return
+	}
+
+	// Normalize the pattern as it's registered in the multiplexer
+	pattern = normalizePattern(pattern)
+	if r.metadata == nil {
+		r.metadata = make(map[string]*Metadata)
+	}
+	r.metadata[pattern] = metadata
+
+	// Set the metadata of the already registered routes
+	for _, route := range r.routes {
+		if !route.mount && (route.Pattern == pattern || route.Pattern == pattern+"{$}") {
+			route.Metadata = metadata
+		}
+	}
 }
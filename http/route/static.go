@@ -0,0 +1,512 @@
+package route
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"log/slog"
+	"mime"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// CacheControlRule is the Cache-Control header value of the static files whose path matches a glob
+	CacheControlRule struct {
+		// Glob is the path.Match glob, matched against the file path relative to the static files root, or against
+		// the file name if the glob doesn't contain a slash, e.g. 'assets/*' or '*.js'
+		Glob string
+
+		// Value is the Cache-Control header value, e.g. 'public, max-age=31536000, immutable'
+		Value string
+	}
+
+	// StaticOptions are the options to serve static files
+	StaticOptions struct {
+		// CacheControlRules are the Cache-Control rules, the first matching rule is applied
+		CacheControlRules []*CacheControlRule
+
+		// DefaultCacheControl is the Cache-Control header value of the files that don't match any rule, not set if
+		// empty
+		DefaultCacheControl string
+
+		// IndexFile is the file served for the directories, and for the SPA fallback
+		IndexFile string
+
+		// SPAFallback serves the root index file for the unknown paths, so the client-side routes are handled by the
+		// single page application
+		SPAFallback bool
+
+		// APIPrefixes are the original path prefixes whose misses are responded with the router not found response
+		// instead of the SPA fallback, e.g. '/api/'
+		APIPrefixes []string
+
+		// Precompressed serves the '.br' and '.gz' siblings of the files when the client accepts their encoding
+		Precompressed bool
+
+		// DirectoryListing lists the directories without index file
+		DirectoryListing bool
+	}
+
+	// staticFileInfo is the cached information of a static file
+	staticFileInfo struct {
+		etag    string
+		modTime time.Time
+		size    int64
+	}
+
+	// staticHandler is the handler that serves the static files of a file system
+	staticHandler struct {
+		router  *Router
+		fsys    fs.FS
+		options *StaticOptions
+		etags   sync.Map
+	}
+)
+
+var (
+	// precompressedEncodings are the precompressed file encodings, in preference order
+	precompressedEncodings = []struct {
+		encoding  string
+		extension string
+	}{
+		{encoding: "br", extension: ".br"},
+		{encoding: "gzip", extension: ".gz"},
+	}
+)
+
+// NewStaticOptions creates new static options with the index file and the precompressed files enabled
+//
+// Returns:
+//
+//   - *StaticOptions: The static options
+func NewStaticOptions() *StaticOptions {
+	return &StaticOptions{
+		IndexFile:     DefaultIndexFile,
+		Precompressed: true,
+	}
+}
+
+// cacheControl returns the Cache-Control header value of a file
+//
+// Parameters:
+//
+//   - name: The file path relative to the static files root
+//
+// Returns:
+//
+//   - string: The Cache-Control header value, empty if it's not set
+func (s *StaticOptions) cacheControl(name string) string {
+	for _, rule := range s.CacheControlRules {
+		if rule == nil {
+			continue
+		}
+
+		target := name
+		if !strings.Contains(rule.Glob, "/") {
+			target = path.Base(name)
+		}
+		if matched, err := path.Match(rule.Glob, target); err == nil && matched {
+			return rule.Value
+		}
+	}
+	return s.DefaultCacheControl
+}
+
+// isAPIPath checks if the original request path has an API prefix
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//
+// Returns:
+//
+//   - bool: True if the path has an API prefix, false otherwise
+func (s *StaticOptions) isAPIPath(r *http.Request) bool {
+	requestPath := requestPath(r)
+	for _, prefix := range s.APIPrefixes {
+		if strings.HasPrefix(requestPath, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsEncoding checks if the request accepts a content encoding
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//   - encoding: The content encoding, e.g. 'br'
+//
+// Returns:
+//
+//   - bool: True if the encoding is accepted, false otherwise
+func acceptsEncoding(r *http.Request, encoding string) bool {
+	for _, header := range r.Header.Values(AcceptEncodingHeader) {
+		for _, value := range strings.Split(header, ",") {
+			name, params, _ := strings.Cut(strings.TrimSpace(value), ";")
+			if !strings.EqualFold(strings.TrimSpace(name), encoding) {
+				continue
+			}
+
+			// Check if the encoding is explicitly refused with a zero quality value
+			quality, found := strings.CutPrefix(strings.ReplaceAll(params, " ", ""), "q=")
+			if !found {
+				return true
+			}
+			value, err := strconv.ParseFloat(quality, 64)
+			return err == nil && value > 0
+		}
+	}
+	return false
+}
+
+// etag returns the content-hash ETag of a file, computed once per file version
+//
+// Parameters:
+//
+//   - name: The file path relative to the static files root
+//   - info: The file info
+//
+// Returns:
+//
+//   - string: The ETag
+//   - error: The error if any
+func (h *staticHandler) etag(name string, info fs.FileInfo) (string, error) {
+	// Check the cached ETag, it's computed again if the file changed
+	if cached, ok := h.etags.Load(name); ok {
+		cachedInfo := cached.(*staticFileInfo)
+		if cachedInfo.modTime.Equal(info.ModTime()) && cachedInfo.size == info.Size() {
+			return cachedInfo.etag, nil
+		}
+	}
+
+	// Hash the file content
+	file, err := h.fsys.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	etag := "\"" + hex.EncodeToString(hash.Sum(nil)[:16]) + "\""
+
+	h.etags.Store(
+		name, &staticFileInfo{
+			etag:    etag,
+			modTime: info.ModTime(),
+			size:    info.Size(),
+		},
+	)
+	return etag, nil
+}
+
+// stat returns the file info of a regular file, or of the index file of a directory
+//
+// Parameters:
+//
+//   - name: The file path relative to the static files root
+//
+// Returns:
+//
+//   - string: The path of the file to serve
+//   - fs.FileInfo: The file info
+//   - bool: True if the path is a directory without index file
+//   - error: The error if any
+func (h *staticHandler) stat(name string) (string, fs.FileInfo, bool, error) {
+	info, err := fs.Stat(h.fsys, name)
+	if err != nil {
+		return "", nil, false, err
+	}
+	if !info.IsDir() {
+		return name, info, false, nil
+	}
+
+	// Get the index file of the directory
+	indexName := path.Join(name, h.options.IndexFile)
+	indexInfo, err := fs.Stat(h.fsys, indexName)
+	if err != nil || indexInfo.IsDir() {
+		return name, info, true, nil
+	}
+	return indexName, indexInfo, false, nil
+}
+
+// serveFile serves a file, or its precompressed sibling if the client accepts its encoding
+//
+// Parameters:
+//
+//   - w: The HTTP response writer
+//   - r: The HTTP request
+//   - name: The file path relative to the static files root
+//   - info: The file info
+func (h *staticHandler) serveFile(
+	w http.ResponseWriter,
+	r *http.Request,
+	name string,
+	info fs.FileInfo,
+) {
+	servedName, servedInfo := name, info
+
+	// Check the precompressed siblings
+	if h.options.Precompressed {
+		w.Header().Add(VaryHeader, AcceptEncodingHeader)
+		for _, precompressed := range precompressedEncodings {
+			if !acceptsEncoding(r, precompressed.encoding) {
+				continue
+			}
+
+			compressedInfo, err := fs.Stat(h.fsys, name+precompressed.extension)
+			if err != nil || compressedInfo.IsDir() {
+				continue
+			}
+
+			servedName, servedInfo = name+precompressed.extension, compressedInfo
+			w.Header().Set(ContentEncodingHeader, precompressed.encoding)
+			break
+		}
+	}
+
+	// Set the content type of the original file, since the precompressed one has another extension
+	if contentType := mime.TypeByExtension(path.Ext(name)); contentType != "" {
+		w.Header().Set(ContentTypeHeader, contentType)
+	}
+
+	// Set the caching headers
+	if cacheControl := h.options.cacheControl(name); cacheControl != "" {
+		w.Header().Set(CacheControlHeader, cacheControl)
+	}
+	etag, err := h.etag(servedName, servedInfo)
+	if err != nil {
+		h.internalError(w, r, servedName, err)
+		return
+	}
+	w.Header().Set(ETagHeader, etag)
+
+	// Open the file
+	file, err := h.fsys.Open(servedName)
+	if err != nil {
+		h.internalError(w, r, servedName, err)
+		return
+	}
+	defer file.Close()
+
+	// Serve the content, handling the conditional and range requests
+	content, ok := file.(io.ReadSeeker)
+	if !ok {
+		data, readErr := io.ReadAll(file)
+		if readErr != nil {
+			h.internalError(w, r, servedName, readErr)
+			return
+		}
+		content = bytes.NewReader(data)
+	}
+	http.ServeContent(w, r, name, servedInfo.ModTime(), content)
+}
+
+// internalError responds to a request whose file couldn't be read
+//
+// Parameters:
+//
+//   - w: The HTTP response writer
+//   - r: The HTTP request
+//   - name: The file path relative to the static files root
+//   - err: The error
+func (h *staticHandler) internalError(
+	w http.ResponseWriter,
+	r *http.Request,
+	name string,
+	err error,
+) {
+	if h.router.logger != nil {
+		h.router.logger.Error(
+			"Failed to serve static file",
+			slog.String("name", name),
+			slog.String("error", err.Error()),
+		)
+	}
+	w.Header().Del(ContentEncodingHeader)
+	w.Header().Del(ETagHeader)
+	http.Error(
+		w,
+		http.StatusText(http.StatusInternalServerError),
+		http.StatusInternalServerError,
+	)
+}
+
+// notFound responds to a request whose file doesn't exist with the router not found response, or with the SPA
+// fallback if enabled
+//
+// Parameters:
+//
+//   - w: The HTTP response writer
+//   - r: The HTTP request
+func (h *staticHandler) notFound(w http.ResponseWriter, r *http.Request) {
+	// Serve the root index file for the client-side routes, the API requests and the missing files with extension
+	// are not routes
+	if h.options.SPAFallback && !h.options.isAPIPath(r) && path.Ext(r.URL.Path) == "" {
+		if info, err := fs.Stat(h.fsys, h.options.IndexFile); err == nil && !info.IsDir() {
+			h.serveFile(w, r, h.options.IndexFile, info)
+			return
+		}
+	}
+	h.router.notFound(w, r)
+}
+
+// ServeHTTP serves the static file of the request path
+//
+// Parameters:
+//
+//   - w: The HTTP response writer
+//   - r: The HTTP request
+func (h *staticHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Only the GET and HEAD requests are allowed
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set(AllowHeader, http.MethodGet+", "+http.MethodHead)
+		h.router.handler.HandleErrorWithCode(
+			w,
+			r,
+			ErrMethodNotAllowed,
+			ErrCodeMethodNotAllowed,
+			http.StatusMethodNotAllowed,
+		)
+		return
+	}
+
+	// Get the file path relative to the static files root
+	name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+	if name == "" {
+		name = "."
+	}
+	if !fs.ValidPath(name) {
+		h.notFound(w, r)
+		return
+	}
+
+	// Get the file to serve
+	servedName, info, isDir, err := h.stat(name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			h.notFound(w, r)
+			return
+		}
+		h.internalError(w, r, name, err)
+		return
+	}
+
+	// Check if the directory can be listed
+	if isDir {
+		if !h.options.DirectoryListing {
+			h.notFound(w, r)
+			return
+		}
+		http.FileServerFS(h.fsys).ServeHTTP(w, r)
+		return
+	}
+	h.serveFile(w, r, servedName, info)
+}
+
+// ServeStaticFS serves the static files of a file system, e.g. an embed.FS, with content-hash ETags, Cache-Control
+// rules, precompressed files and SPA fallback
+//
+// Parameters:
+//
+//   - pattern: The pattern to serve the static files, e.g. '/assets/' or 'GET /'
+//   - fsys: The file system
+//   - options: The static options, if nil the default ones are used
+func (r *Router) ServeStaticFS(
+	pattern string,
+	fsys fs.FS,
+	options *StaticOptions,
+) {
+	if r == nil {
+		return
+	}
+
+	// Check if the file system is nil
+	if fsys == nil {
+		panic(ErrNilFileSystem)
+	}
+
+	// Copy the options, so the default values are not set on the caller ones
+	staticOptions := NewStaticOptions()
+	if options != nil {
+		*staticOptions = *options
+		staticOptions.CacheControlRules = slices.Clone(options.CacheControlRules)
+		staticOptions.APIPrefixes = slices.Clone(options.APIPrefixes)
+	}
+	if staticOptions.IndexFile == "" {
+		staticOptions.IndexFile = DefaultIndexFile
+	}
+
+	// Split the method and path from the pattern, and add the trailing slash to the path
+	method, prefix, err := SplitPattern(pattern)
+	if err != nil {
+		panic(err)
+	}
+	if prefix[len(prefix)-1] != '/' {
+		prefix += "/"
+	}
+	pattern = prefix
+	if method != "" {
+		pattern = method + " " + prefix
+	}
+
+	// Serve the static files
+	handler := http.StripPrefix(
+		prefix,
+		&staticHandler{
+			router:  r,
+			fsys:    fsys,
+			options: staticOptions,
+		},
+	)
+	r.mux.Handle(pattern, handler)
+	r.addRoute(pattern, handler, true)
+
+	if r.logger != nil && r.mode != nil && r.mode.IsDebug() {
+		r.logger.Debug(
+			"Serving static files",
+			slog.String("full_path", r.fullPath),
+			slog.String("pattern", pattern),
+		)
+	}
+}
+
+// ServeStaticFiles serves the static files of a directory with http.FileServer, which lists the directories without
+// index file and responds the missing files with a plain text not found response. Use ServeStaticFS with os.DirFS to
+// serve them with the static options
+//
+// Parameters:
+//
+//   - pattern: The pattern to serve the static files
+//   - path: The path to the static files
+func (r *Router) ServeStaticFiles(
+	pattern,
+	path string,
+) {
+	if r == nil {
+		return
+	}
+
+	// Check if the pattern contains a trailing slash and add it
+	if pattern == "" || pattern[len(pattern)-1] != '/' {
+		pattern += "/"
+	}
+
+	// Serve the static files
+	staticHandler := http.StripPrefix(pattern, http.FileServer(http.Dir(path)))
+	r.mux.Handle(pattern, staticHandler)
+	r.addRoute(pattern, staticHandler, true)
+}
@@ -0,0 +1,67 @@
+package route
+
+import (
+	"net/http"
+)
+
+type (
+	// Route is a route registered in a router
+	Route struct {
+		// Method is the method of the route, empty if the route matches any method
+		Method string
+
+		// Path is the path registered in the router multiplexer
+		Path string
+
+		// Pattern is the pattern registered in the router multiplexer, e.g. 'GET /users/{id}'
+		Pattern string
+
+		// FullPath is the path of the route joined with the full path of its router, e.g. '/v1/users/{id}'
+		FullPath string
+
+		// Name is the name of the route used to build its URL, empty if the route is unnamed
+		Name string
+
+		// Metadata is the metadata of the route, nil if the route has no metadata
+		Metadata *Metadata
+
+		handler              http.Handler
+		router               RouterWrapper
+		exclusions           *namedExclusions
+		mount                bool
+		disableAutomaticHead bool
+	}
+
+	// Metadata is the metadata of a route, readable by its middlewares from the request context, so the middlewares
+	// applied to a whole module can adapt to each route
+	Metadata struct {
+		// RPCMethod is the gRPC method the route is a gateway to, e.g. '/users.v1.UsersService/GetUser'
+		RPCMethod string
+
+		// Scopes are the scopes required to access the route
+		Scopes []string
+
+		// RateLimitPolicy is the name of the rate limit policy of the route
+		RateLimitPolicy string
+
+		// Tags are the tags of the route, e.g. the ones used to group the routes in the API documentation
+		Tags []string
+
+		// Summary is the short description of the route
+		Summary string
+
+		// Extra is the custom metadata of the route
+		Extra map[string]any
+	}
+
+	// NotFoundHandlerFn is the function to handle the requests that don't match any route
+	NotFoundHandlerFn func(w http.ResponseWriter, r *http.Request)
+
+	// MethodNotAllowedHandlerFn is the function to handle the requests whose path matches a route, but not its method.
+	// The Allow header is already set when it's called
+	MethodNotAllowedHandlerFn func(
+		w http.ResponseWriter,
+		r *http.Request,
+		allowedMethods []string,
+	)
+)
@@ -0,0 +1,252 @@
+package route
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+type (
+	// routeNames is the registry of the named routes, shared by a router and its sub-routers
+	routeNames struct {
+		routes  map[string]*Route
+		baseURL *url.URL
+		mutex   sync.RWMutex
+	}
+)
+
+// newRouteNames creates a new route names registry
+//
+// Returns:
+//
+//   - *routeNames: The route names registry
+func newRouteNames() *routeNames {
+	return &routeNames{
+		routes: make(map[string]*Route),
+	}
+}
+
+// has checks if a route name is registered
+//
+// Parameters:
+//
+//   - name: The name of the route
+//
+// Returns:
+//
+//   - bool: True if the route name is registered, false otherwise
+func (n *routeNames) has(name string) bool {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+
+	_, ok := n.routes[name]
+	return ok
+}
+
+// add registers a named route
+//
+// Parameters:
+//
+//   - route: The named route
+func (n *routeNames) add(route *Route) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	n.routes[route.Name] = route
+}
+
+// get returns a named route
+//
+// Parameters:
+//
+//   - name: The name of the route
+//
+// Returns:
+//
+//   - *Route: The route, nil if the route name is not registered
+func (n *routeNames) get(name string) *Route {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+
+	return n.routes[name]
+}
+
+// BuildPath replaces the wildcards of a route path with the given parameters. The parameters are escaped, except
+// the ones of the remaining path wildcards, e.g. '{path...}', whose slashes are kept
+//
+// Parameters:
+//
+//   - path: The route path, e.g. '/users/{id}'
+//   - params: The wildcards values by name
+//
+// Returns:
+//
+//   - string: The built path
+//   - error: The error if any
+func BuildPath(path string, params map[string]string) (string, error) {
+	// Get the wildcards from the path
+	parsedPath, wildcards, err := GetWildcards(path)
+	if err != nil {
+		return "", err
+	}
+
+	// Check the extra parameters
+	names := make(map[string]struct{}, len(wildcards))
+	for _, wildcard := range wildcards {
+		names[strings.TrimSuffix(wildcard, "...")] = struct{}{}
+	}
+	for param := range params {
+		if _, ok := names[param]; !ok {
+			return "", fmt.Errorf(ErrUnexpectedRouteParam, param, path)
+		}
+	}
+
+	var builder strings.Builder
+	for i := 0; i < len(parsedPath); i++ {
+		if parsedPath[i] != '{' {
+			builder.WriteByte(parsedPath[i])
+			continue
+		}
+
+		// Get the wildcard name, it's already checked to be closed
+		j := strings.IndexByte(parsedPath[i:], '}')
+		wildcard := parsedPath[i+1 : i+j]
+		i += j
+
+		// The '{$}' wildcard only matches the end of the path
+		if wildcard == "$" {
+			continue
+		}
+
+		// Get the parameter value
+		name := strings.TrimSuffix(wildcard, "...")
+		value, ok := params[name]
+		if !ok {
+			return "", fmt.Errorf(ErrMissingRouteParam, name, path)
+		}
+
+		// Escape the parameter value, keeping the slashes of the remaining path wildcards
+		if name != wildcard {
+			segments := strings.Split(value, "/")
+			for k, segment := range segments {
+				segments[k] = url.PathEscape(segment)
+			}
+			builder.WriteString(strings.Join(segments, "/"))
+		} else {
+			builder.WriteString(url.PathEscape(value))
+		}
+	}
+	return builder.String(), nil
+}
+
+// URL builds the path of a named route registered in the router or in any router of its tree
+//
+// Parameters:
+//
+//   - name: The name of the route
+//   - params: The wildcards values by name, all the route wildcards must be set
+//   - query: The query parameters to add to the URL, can be nil
+//
+// Returns:
+//
+//   - string: The route URL, e.g. '/v1/users/1?expand=roles'
+//   - error: The error if any
+func (r *Router) URL(
+	name string,
+	params map[string]string,
+	query url.Values,
+) (string, error) {
+	if r == nil {
+		return "", ErrNilRouter
+	}
+
+	// Get the named route
+	route := r.names.get(name)
+	if route == nil {
+		return "", fmt.Errorf(ErrRouteNameNotFound, name)
+	}
+
+	// Build the path
+	path, err := BuildPath(route.FullPath, params)
+	if err != nil {
+		return "", err
+	}
+
+	// Add the query parameters
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return path, nil
+}
+
+// AbsoluteURL builds the absolute URL of a named route, using the external base URL set with SetBaseURL
+//
+// Parameters:
+//
+//   - name: The name of the route
+//   - params: The wildcards values by name, all the route wildcards must be set
+//   - query: The query parameters to add to the URL, can be nil
+//
+// Returns:
+//
+//   - string: The absolute route URL, e.g. 'https://api.example.com/v1/users/1'
+//   - error: The error if any
+func (r *Router) AbsoluteURL(
+	name string,
+	params map[string]string,
+	query url.Values,
+) (string, error) {
+	if r == nil {
+		return "", ErrNilRouter
+	}
+
+	// Get the base URL
+	r.names.mutex.RLock()
+	baseURL := r.names.baseURL
+	r.names.mutex.RUnlock()
+	if baseURL == nil {
+		return "", ErrNilBaseURL
+	}
+
+	// Build the route URL
+	path, err := r.URL(name, params, query)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(baseURL.String(), "/") + path, nil
+}
+
+// SetBaseURL sets the external base URL used to build the absolute URLs of the router and of every router of its
+// tree, e.g. 'https://api.example.com' or 'https://example.com/api' when served behind a proxy prefix
+//
+// Parameters:
+//
+//   - baseURL: The external base URL
+//
+// Returns:
+//
+//   - error: The error if any
+func (r *Router) SetBaseURL(baseURL string) error {
+	if r == nil {
+		return ErrNilRouter
+	}
+
+	// Parse the base URL
+	parsedURL, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf(ErrInvalidBaseURL, baseURL, err)
+	}
+	if parsedURL.Scheme == "" || parsedURL.Host == "" {
+		return fmt.Errorf(ErrInvalidBaseURL, baseURL, "missing scheme or host")
+	}
+
+	// Remove the query and fragment of the base URL
+	parsedURL.RawQuery = ""
+	parsedURL.Fragment = ""
+
+	r.names.mutex.Lock()
+	r.names.baseURL = parsedURL
+	r.names.mutex.Unlock()
+	return nil
+}
@@ -51,13 +51,16 @@ func GetWildcards(pattern string) (parsedPattern string, wildcards []string, err
 
 // SplitPattern returns the method and the path from the pattern
 //
+// A pattern that starts with '/' has no method and matches any method, so '/users' returns an empty method and the
+// '/users' path
+//
 // Parameters:
 //
 //   - pattern: The pattern to split
 //
 // Returns:
 //
-//   - string: The method
+//   - string: The method, empty if the pattern has no method
 //   - string: The path
 //   - error: The error if any
 func SplitPattern(pattern string) (method, path string, err error) {
@@ -69,6 +72,11 @@ func SplitPattern(pattern string) (method, path string, err error) {
 		return "", "", ErrEmptyPattern
 	}
 
+	// Check if the pattern doesn't contain a method, in that case it matches any method
+	if pattern[0] == '/' {
+		return "", pattern, nil
+	}
+
 	// Split the pattern by space
 	parts := strings.SplitN(pattern, " ", 2)
 
@@ -83,3 +91,67 @@ func SplitPattern(pattern string) (method, path string, err error) {
 
 	return method, path, nil
 }
+
+// JoinPaths joins a base path and a relative path, avoiding duplicated slashes
+//
+// Parameters:
+//
+//   - basePath: The base path
+//   - relativePath: The relative path
+//
+// Returns:
+//
+//   - string: The joined path
+func JoinPaths(basePath, relativePath string) string {
+	switch {
+	case relativePath == "" || relativePath == "/":
+		if basePath == "" {
+			return "/"
+		}
+		return basePath
+	case basePath == "" || basePath == "/":
+		return relativePath
+	case basePath[len(basePath)-1] == '/':
+		return basePath + relativePath[1:]
+	default:
+		return basePath + relativePath
+	}
+}
+
+// joinRoutePath joins the full path of a router and the path of one of its routes, keeping the trailing slash of the
+// route path
+//
+// Parameters:
+//
+//   - basePath: The full path of the router
+//   - path: The path of the route
+//
+// Returns:
+//
+//   - string: The joined path
+func joinRoutePath(basePath, path string) string {
+	if path == "" || path[0] != '/' {
+		path = "/" + path
+	}
+	return strings.TrimSuffix(basePath, "/") + path
+}
+
+// normalizePattern normalizes a route pattern as it's registered in the multiplexer
+//
+// Parameters:
+//
+//   - pattern: The pattern, e.g. 'get  /users'
+//
+// Returns:
+//
+//   - string: The normalized pattern, e.g. 'GET /users'
+func normalizePattern(pattern string) string {
+	method, path, err := SplitPattern(pattern)
+	if err != nil {
+		panic(err)
+	}
+	if method == "" {
+		return path
+	}
+	return method + " " + path
+}
@@ -0,0 +1,464 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	gonethttpctx "github.com/ralvarezdev/go-net/http/context"
+	gonethttphandler "github.com/ralvarezdev/go-net/http/handler"
+	gonethttproute "github.com/ralvarezdev/go-net/http/route"
+)
+
+type (
+	// VersioningStrategy is the strategy used to resolve the API version of a request
+	VersioningStrategy int
+
+	// versionPathCtxKey is the context key for the request path relative to the versioned module router, including
+	// the version prefix
+	versionPathCtxKey struct{}
+
+	// Version is an API version of a versioned module
+	Version struct {
+		// Name is the name of the version, used as its path prefix, e.g. 'v2'
+		Name string
+
+		// Inherits is the name of a previously declared version whose routes are served when they're not
+		// overridden by this version, empty if the version doesn't inherit any route
+		Inherits string
+
+		// Middlewares are applied to the routes of the version
+		Middlewares []func(next http.Handler) http.Handler
+
+		// Submodules are created with the version router as their base router
+		Submodules []*Module
+
+		// Deprecated sets the Deprecation header on the responses of the version
+		Deprecated bool
+
+		// DeprecatedAt is the date since the version is deprecated, if zero the Deprecation header is set to 'true'
+		DeprecatedAt time.Time
+
+		// SunsetAt is the date when the version will stop being served, set as the Sunset header if not zero
+		SunsetAt time.Time
+
+		// DeprecationLink is the URL of the deprecation policy, set as a Link header with the 'deprecation' relation
+		DeprecationLink string
+
+		// SunsetLink is the URL of the sunset policy, set as a Link header with the 'sunset' relation
+		SunsetLink string
+
+		gonethttproute.RouterWrapper
+	}
+
+	// VersionedModule is the struct for a route module served in several API versions
+	VersionedModule struct {
+		// Pattern is the pattern of the module, the versions are served under it
+		Pattern string
+
+		// Strategy is the strategy used to resolve the API version of the requests
+		Strategy VersioningStrategy
+
+		// HeaderName is the header used by the header strategy, DefaultVersionHeader if empty
+		HeaderName string
+
+		// Vendor is the vendor of the media types used by the media type strategy, e.g. 'x' for
+		// 'application/vnd.x.v2+json'
+		Vendor string
+
+		// DefaultVersion is the version used by the header and media type strategies when the request doesn't
+		// specify one, the last declared version if empty
+		DefaultVersion string
+
+		// Handler responds to the requests with an unsupported version
+		Handler gonethttphandler.Handler
+
+		Middlewares []func(next http.Handler) http.Handler
+		Versions    []*Version
+		gonethttproute.RouterWrapper
+
+		versions map[string]*Version
+	}
+)
+
+const (
+	// VersioningPath resolves the version from the first path segment, e.g. '/api/v2/users'
+	VersioningPath VersioningStrategy = iota
+
+	// VersioningHeader resolves the version from a custom header, e.g. 'X-API-Version: v2'
+	VersioningHeader
+
+	// VersioningMediaType resolves the version from the Accept header vendor media type, e.g.
+	// 'Accept: application/vnd.x.v2+json'
+	VersioningMediaType
+)
+
+// NewVersions is a function that creates a new versions slice
+//
+// Usage: NewVersions(version1, version2, ...)
+//
+// Parameters:
+//
+//   - versions: variadic list of version pointers
+//
+// Returns:
+//
+//   - []*Version: Slice of version pointers
+func NewVersions(versions ...*Version) []*Version {
+	return versions
+}
+
+// Create is a function that creates the router of the versioned module and the routers of its versions, and loads
+// their submodules
+//
+// Parameters:
+//
+//   - baseRouter: The base router to create the module's router group
+//
+// Returns:
+//
+//   - error: The error if any
+func (m *VersionedModule) Create(
+	baseRouter gonethttproute.RouterWrapper,
+) error {
+	if m == nil {
+		return ErrNilVersionedModule
+	}
+
+	// Check if the base route is nil
+	if baseRouter == nil {
+		return gonethttproute.ErrNilRouter
+	}
+
+	// Check if the handler is nil
+	if m.Handler == nil {
+		return gonethttphandler.ErrNilHandler
+	}
+
+	// Check the versions
+	if len(m.Versions) == 0 {
+		return ErrNoVersions
+	}
+	if m.Strategy == VersioningMediaType && m.Vendor == "" {
+		return ErrNilVendor
+	}
+	if m.HeaderName == "" {
+		m.HeaderName = DefaultVersionHeader
+	}
+
+	m.versions = make(map[string]*Version, len(m.Versions))
+	for i, version := range m.Versions {
+		if version == nil {
+			return fmt.Errorf(ErrNilVersion, m.Pattern, i)
+		}
+		if version.Name == "" {
+			return fmt.Errorf(ErrEmptyVersionName, m.Pattern, i)
+		}
+		if _, ok := m.versions[version.Name]; ok {
+			return fmt.Errorf(ErrDuplicateVersion, m.Pattern, version.Name)
+		}
+
+		// Check if the inherited version was declared before, so its router is already created
+		if version.Inherits != "" {
+			if _, ok := m.versions[version.Inherits]; !ok {
+				return fmt.Errorf(
+					ErrUnknownInheritedVersion,
+					m.Pattern,
+					version.Name,
+					version.Inherits,
+				)
+			}
+		}
+		m.versions[version.Name] = version
+	}
+
+	// Check the default version
+	if m.DefaultVersion == "" {
+		m.DefaultVersion = m.Versions[len(m.Versions)-1].Name
+	} else if _, ok := m.versions[m.DefaultVersion]; !ok {
+		return fmt.Errorf(ErrUnknownDefaultVersion, m.Pattern, m.DefaultVersion)
+	}
+
+	// Set the base route, resolving the version before the module middlewares
+	var err error
+	middlewares := append(
+		[]func(next http.Handler) http.Handler{m.resolveVersionMiddleware},
+		m.Middlewares...,
+	)
+	m.RouterWrapper, err = baseRouter.NewRouter(m.Pattern, middlewares...)
+	if err != nil {
+		return err
+	}
+
+	// Create the versions routers
+	for _, version := range m.Versions {
+		version.RouterWrapper, err = m.NewRouter(
+			"/"+version.Name,
+			version.Middlewares...,
+		)
+		if err != nil {
+			return err
+		}
+
+		// Serve the routes not overridden by the version with the inherited version, before creating the
+		// submodules so they inherit it
+		if version.Inherits != "" {
+			inheritFn := m.inheritedVersionHandlerFn(version, m.versions[version.Inherits])
+			version.SetNotFoundHandler(inheritFn)
+			version.SetMethodNotAllowedHandler(
+				func(w http.ResponseWriter, r *http.Request, _ []string) {
+					inheritFn(w, r)
+				},
+			)
+		}
+
+		// Create the submodules router
+		for i, submodule := range version.Submodules {
+			if submodule == nil {
+				return fmt.Errorf(ErrNilSubmodule, version.Name, i)
+			}
+
+			if createErr := submodule.Create(version.RouterWrapper); createErr != nil {
+				return createErr
+			}
+		}
+	}
+	return nil
+}
+
+// GetRouter returns the router
+//
+// Returns:
+//
+//   - gonethttproute.RouterWrapper: The router
+func (m *VersionedModule) GetRouter() gonethttproute.RouterWrapper {
+	if m == nil {
+		return nil
+	}
+	return m.RouterWrapper
+}
+
+// GetVersion returns a declared version by its name
+//
+// Parameters:
+//
+//   - name: The name of the version
+//
+// Returns:
+//
+//   - *Version: The version, nil if it's not declared or the module is not created
+func (m *VersionedModule) GetVersion(name string) *Version {
+	if m == nil {
+		return nil
+	}
+	return m.versions[name]
+}
+
+// lookupVersion returns a declared version by its name, also accepting the name without the 'v' prefix
+//
+// Parameters:
+//
+//   - name: The name of the version, e.g. 'v2' or '2'
+//
+// Returns:
+//
+//   - *Version: The version, nil if it's not declared
+func (m *VersionedModule) lookupVersion(name string) *Version {
+	if version, ok := m.versions[name]; ok {
+		return version
+	}
+	return m.versions["v"+name]
+}
+
+// mediaTypeVersion returns the version name of the first vendor media type of the Accept header
+//
+// Parameters:
+//
+//   - r: The HTTP request
+//
+// Returns:
+//
+//   - string: The version name, empty if the Accept header doesn't contain a vendor media type
+func (m *VersionedModule) mediaTypeVersion(r *http.Request) string {
+	prefix := "application/vnd." + strings.ToLower(m.Vendor) + "."
+	for _, header := range r.Header.Values(Accept) {
+		for _, mediaRange := range strings.Split(header, ",") {
+			mediaType, _, err := mime.ParseMediaType(mediaRange)
+			if err != nil || !strings.HasPrefix(mediaType, prefix) {
+				continue
+			}
+
+			// Remove the structured syntax suffix, e.g. '+json'
+			name := strings.TrimPrefix(mediaType, prefix)
+			if i := strings.IndexByte(name, '+'); i != -1 {
+				name = name[:i]
+			}
+			return name
+		}
+	}
+	return ""
+}
+
+// resolveVersionMiddleware resolves the version of the request, rewriting its path to the version prefix when it's
+// resolved from the headers, and sets the deprecation headers of the version
+//
+// Parameters:
+//
+//   - next: The next handler
+//
+// Returns:
+//
+//   - http.Handler: The middleware handler
+func (m *VersionedModule) resolveVersionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			var version *Version
+			switch m.Strategy {
+			case VersioningHeader, VersioningMediaType:
+				// Get the requested version name
+				var name string
+				status := http.StatusBadRequest
+				if m.Strategy == VersioningHeader {
+					w.Header().Add(Vary, m.HeaderName)
+					name = strings.TrimSpace(r.Header.Get(m.HeaderName))
+				} else {
+					w.Header().Add(Vary, Accept)
+					name = m.mediaTypeVersion(r)
+					status = http.StatusNotAcceptable
+				}
+
+				// Get the version, the default one if not specified
+				if name == "" {
+					name = m.DefaultVersion
+				}
+				if version = m.lookupVersion(name); version == nil {
+					m.Handler.HandleErrorWithCode(
+						w,
+						r,
+						ErrUnsupportedVersion,
+						ErrCodeUnsupportedVersion,
+						status,
+					)
+					return
+				}
+
+				// Rewrite the path to the version prefix
+				r = r.Clone(r.Context())
+				r.URL.Path = "/" + version.Name + r.URL.Path
+				if r.URL.RawPath != "" {
+					r.URL.RawPath = "/" + version.Name + r.URL.RawPath
+				}
+			default:
+				// Get the version from the first path segment, if it's not declared the router responds with a 404.
+				// The compiled routers keep the original path, so the module path is also removed
+				version = m.versions[firstPathSegment(r.URL.Path)]
+				if version == nil {
+					path := strings.TrimPrefix(r.URL.Path, strings.TrimSuffix(m.FullPath(), "/"))
+					version = m.versions[firstPathSegment(path)]
+				}
+			}
+
+			if version != nil {
+				version.setDeprecationHeaders(w)
+				r = gonethttpctx.SetCtxAPIVersion(r, version.Name)
+			}
+
+			// Keep the path relative to the module router, used to serve the inherited routes
+			r = r.WithContext(context.WithValue(r.Context(), versionPathCtxKey{}, r.URL.Path))
+			next.ServeHTTP(w, r)
+		},
+	)
+}
+
+// inheritedVersionHandlerFn returns the handler that serves the requests not matched by a version with the version
+// it inherits from
+//
+// Parameters:
+//
+//   - version: The version
+//   - inherited: The inherited version
+//
+// Returns:
+//
+//   - gonethttproute.NotFoundHandlerFn: The handler function
+func (m *VersionedModule) inheritedVersionHandlerFn(
+	version, inherited *Version,
+) gonethttproute.NotFoundHandlerFn {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the path relative to the module router, the version routers only see their relative path
+		path, ok := r.Context().Value(versionPathCtxKey{}).(string)
+		if !ok {
+			path = "/" + version.Name + r.URL.Path
+		}
+		path = strings.TrimPrefix(path, "/"+version.Name)
+		if path == "" {
+			path = "/"
+		}
+
+		// Remove the Allow header set by the version router
+		w.Header().Del(gonethttproute.AllowHeader)
+
+		// Serve the request with the inherited version router
+		inheritedRequest := r.Clone(
+			context.WithValue(
+				r.Context(),
+				versionPathCtxKey{},
+				"/"+inherited.Name+path,
+			),
+		)
+		inheritedRequest.URL.Path = path
+		inheritedRequest.URL.RawPath = ""
+		inherited.Handler().ServeHTTP(w, inheritedRequest)
+	}
+}
+
+// firstPathSegment returns the first segment of a path
+//
+// Parameters:
+//
+//   - path: The path, e.g. '/v2/users'
+//
+// Returns:
+//
+//   - string: The first segment, e.g. 'v2'
+func firstPathSegment(path string) string {
+	segment := strings.TrimPrefix(path, "/")
+	if i := strings.IndexByte(segment, '/'); i != -1 {
+		segment = segment[:i]
+	}
+	return segment
+}
+
+// setDeprecationHeaders sets the Deprecation, Sunset and Link headers of a deprecated version
+//
+// Parameters:
+//
+//   - w: The HTTP response writer
+func (v *Version) setDeprecationHeaders(w http.ResponseWriter) {
+	if v == nil {
+		return
+	}
+
+	// Set the Deprecation header
+	if v.Deprecated {
+		if v.DeprecatedAt.IsZero() {
+			w.Header().Set(Deprecation, "true")
+		} else {
+			w.Header().Set(Deprecation, fmt.Sprintf("@%d", v.DeprecatedAt.Unix()))
+		}
+		if v.DeprecationLink != "" {
+			w.Header().Add(Link, fmt.Sprintf("<%s>; rel=\"deprecation\"", v.DeprecationLink))
+		}
+	}
+
+	// Set the Sunset header
+	if !v.SunsetAt.IsZero() {
+		w.Header().Set(Sunset, v.SunsetAt.UTC().Format(http.TimeFormat))
+		if v.SunsetLink != "" {
+			w.Header().Add(Link, fmt.Sprintf("<%s>; rel=\"sunset\"", v.SunsetLink))
+		}
+	}
+}
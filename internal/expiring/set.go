@@ -0,0 +1,192 @@
+package expiring
+
+import (
+	"container/heap"
+	"time"
+)
+
+type (
+	// entry is a key of the set with its expiration time and its index in the expiration heap
+	entry struct {
+		key       string
+		expiresAt time.Time
+		index     int
+	}
+
+	// expirationHeap is the min-heap of the expiration times of the keys
+	expirationHeap []*entry
+
+	// Set is a set of keys with an expiration time, which removes the expired keys popping only them from a min-heap.
+	// It's not safe for concurrent use, the callers must hold their own lock
+	Set struct {
+		entries     map[string]*entry
+		expirations expirationHeap
+	}
+)
+
+// NewSet creates a new expiring set
+//
+// Returns:
+//
+//   - *Set: The expiring set
+func NewSet() *Set {
+	return &Set{
+		entries: make(map[string]*entry),
+	}
+}
+
+// Len returns the number of expiration times
+//
+// Returns:
+//
+//   - int: The number of expiration times
+func (e expirationHeap) Len() int {
+	return len(e)
+}
+
+// Less checks if an expiration time is before another one
+//
+// Parameters:
+//
+//   - i: The index of the first expiration time
+//   - j: The index of the second expiration time
+//
+// Returns:
+//
+//   - bool: True if the first expiration time is before the second one
+func (e expirationHeap) Less(i, j int) bool {
+	return e[i].expiresAt.Before(e[j].expiresAt)
+}
+
+// Swap swaps two expiration times
+//
+// Parameters:
+//
+//   - i: The index of the first expiration time
+//   - j: The index of the second expiration time
+func (e expirationHeap) Swap(i, j int) {
+	e[i], e[j] = e[j], e[i]
+	e[i].index = i
+	e[j].index = j
+}
+
+// Push adds an expiration time, used by container/heap
+//
+// Parameters:
+//
+//   - value: The expiration time
+func (e *expirationHeap) Push(value any) {
+	added := value.(*entry)
+	added.index = len(*e)
+	*e = append(*e, added)
+}
+
+// Pop removes the last expiration time, used by container/heap
+//
+// Returns:
+//
+//   - any: The removed expiration time
+func (e *expirationHeap) Pop() any {
+	old := *e
+	last := old[len(old)-1]
+	old[len(old)-1] = nil
+	*e = old[:len(old)-1]
+	return last
+}
+
+// Add adds a key with its expiration time, replacing the expiration time if the key is already in the set
+//
+// Parameters:
+//
+//   - key: The key
+//   - expiresAt: The expiration time
+func (s *Set) Add(key string, expiresAt time.Time) {
+	if s == nil {
+		return
+	}
+
+	if stored, ok := s.entries[key]; ok {
+		stored.expiresAt = expiresAt
+		heap.Fix(&s.expirations, stored.index)
+		return
+	}
+
+	added := &entry{key: key, expiresAt: expiresAt}
+	s.entries[key] = added
+	heap.Push(&s.expirations, added)
+}
+
+// Remove removes a key with its expiration time
+//
+// Parameters:
+//
+//   - key: The key
+func (s *Set) Remove(key string) {
+	if s == nil {
+		return
+	}
+
+	if stored, ok := s.entries[key]; ok {
+		heap.Remove(&s.expirations, stored.index)
+		delete(s.entries, key)
+	}
+}
+
+// ExpiresAt returns the expiration time of a key
+//
+// Parameters:
+//
+//   - key: The key
+//
+// Returns:
+//
+//   - time.Time: The expiration time
+//   - bool: True if the key is in the set
+func (s *Set) ExpiresAt(key string) (time.Time, bool) {
+	if s == nil {
+		return time.Time{}, false
+	}
+
+	stored, ok := s.entries[key]
+	if !ok {
+		return time.Time{}, false
+	}
+	return stored.expiresAt, true
+}
+
+// Has checks if a key is in the set
+//
+// Parameters:
+//
+//   - key: The key
+//
+// Returns:
+//
+//   - bool: True if the key is in the set
+func (s *Set) Has(key string) bool {
+	_, ok := s.ExpiresAt(key)
+	return ok
+}
+
+// RemoveExpired removes the keys expired at the given time, popping only them from the expiration heap
+//
+// Parameters:
+//
+//   - now: The current time
+//
+// Returns:
+//
+//   - []string: The removed keys
+func (s *Set) RemoveExpired(now time.Time) []string {
+	if s == nil {
+		return nil
+	}
+
+	var removed []string
+	for s.expirations.Len() > 0 && now.After(s.expirations[0].expiresAt) {
+		expired := heap.Pop(&s.expirations).(*entry)
+		delete(s.entries, expired.key)
+		removed = append(removed, expired.key)
+	}
+	return removed
+}